@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/craigk5n/wordsearch/puzzle"
+)
+
+// bookletCommand implements "wordsearch booklet", which generates every puzzle in the
+// YAML files and writes them all to one PDF with a table of contents and an answer key.
+// The page size, orientation, margins and fonts come from the first YAML file.
+// It returns the process exit code.
+func bookletCommand(args []string) int {
+	flags := flag.NewFlagSet("booklet", flag.ExitOnError)
+	outputFile := flags.String("o", "booklet.pdf", "PDF file to write")
+	title := flags.String("t", "Word Search Puzzles", "Title printed above the table of contents")
+	dictionaryPath := flags.String("d", "", "Custom dictionary file (optional)")
+	verbose := flags.Bool("v", false, "enable debug logging")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s booklet [-o booklet.pdf] [-t title] config.yaml...\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		fmt.Println("Error: at least one YAML input file is required.")
+		flags.Usage()
+		return 1
+	}
+
+	var puzzles []puzzle.BookletPuzzle
+	var page puzzle.PageSetup
+	var options puzzle.RenderOptions
+	for _, inputFile := range flags.Args() {
+		configs, err := puzzle.ParseConfigs(inputFile)
+		if err != nil {
+			fmt.Printf("Error: Failed to parse YAML input file %s: %v\n", inputFile, err)
+			return 1
+		}
+		for _, config := range configs {
+			if len(puzzles) == 0 {
+				page, options = config.PageSetup, config.RenderOptions()
+			}
+			p, err := generatePuzzle(config, *dictionaryPath, *verbose)
+			if err != nil {
+				fmt.Printf("Error: Failed to generate puzzle %s for %s: %v\n", config.OutputBasename, inputFile, err)
+				return 1
+			}
+			puzzles = append(puzzles, puzzle.BookletPuzzle{Puzzle: p, Title: config.Title,
+				Words: config.DisplayWords(), Columns: config.Columns, Background: config.Background})
+		}
+	}
+
+	if err := puzzle.GenerateBooklet(*title, puzzles, page, options, *outputFile); err != nil {
+		fmt.Printf("Error generating booklet: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote %d puzzles to %s\n", len(puzzles), *outputFile)
+	return 0
+}
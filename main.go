@@ -1,19 +1,39 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/craigk5n/wordsearch/puzzle"
 )
 
-const max_puzzle_size int = 1024
+const max_puzzle_size int = puzzle.MaxPuzzleSize
 
 func main() {
-	inputFile := flag.String("i", "", "YAML input file")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "verify":
+			os.Exit(verifyCommand(os.Args[2:]))
+		case "booklet":
+			os.Exit(bookletCommand(os.Args[2:]))
+		case "validate":
+			os.Exit(validateCommand(os.Args[2:]))
+		}
+	}
+
+	inputFile := flag.String("i", "", "YAML input file (or a JSON puzzle to render again)")
 	dictionaryPath := flag.String("d", "", "Custom dictionary file (optional)")
 	verbose := flag.Bool("v", false, "enable debug logging")
+	seed := flag.Int64("seed", 0, "Random seed used to generate the puzzle (optional, overrides the config)")
+	format := flag.String("format", "pdf", "Output format: pdf, html, svg, json, png or jpeg")
+	dpi := flag.Float64("dpi", 150, "Resolution of PNG and JPEG output")
+	interactive := flag.Bool("interactive", true, "Let players select words in HTML output")
 
 	flag.Parse()
 
@@ -23,47 +43,162 @@ func main() {
 		os.Exit(1)
 	}
 
-	config, err := puzzle.ParseConfig(*inputFile)
+	if strings.EqualFold(filepath.Ext(*inputFile), ".json") {
+		// Render a previously generated puzzle
+		p, err := puzzle.LoadPuzzleFromJSONFile(*inputFile)
+		if err != nil {
+			fmt.Printf("Error: Failed to load JSON puzzle: %v\n", err)
+			os.Exit(1)
+		}
+		base := filepath.Base(*inputFile)
+		config := &puzzle.PuzzleConfig{Title: p.Title(), Words: p.Entries(),
+			OutputBasename: strings.TrimSuffix(base, filepath.Ext(base))}
+		writePuzzle(config, p, *format, *dpi, *interactive)
+		return
+	}
+
+	// The YAML file may hold several puzzles; generate each of them
+	configs, err := puzzle.ParseConfigs(*inputFile)
 	if err != nil {
 		fmt.Printf("Error: Failed to parse YAML input file: %v\n", err)
 		os.Exit(1)
 	}
-
-	// We either generate a puzzle of the specified size, or we start with the max word length
-	// and keep adding 1 until we successfully generate the puzzle.
-	autoSize := (config.Size == 0)
-	fmt.Printf("size=%d, autoSize=%v\n", config.Size, autoSize)
-	if autoSize {
-		// Set puzzle size to longest word
-		for _, word := range config.Words {
-			if len(word) > config.Size {
-				config.Size = len(word)
-			}
+	for _, config := range configs {
+		if *seed != 0 {
+			config.Seed = *seed
 		}
+		p, err := generatePuzzle(config, *dictionaryPath, *verbose)
+		if err != nil {
+			fmt.Printf("Error: Failed to generate puzzle %s: %v\n", config.OutputBasename, err)
+			os.Exit(1)
+		}
+		writePuzzle(config, p, *format, *dpi, *interactive)
 	}
-	p, err := puzzle.GeneratePuzzle(config.Size, config.Words, config.Columns, config.Difficulty, *dictionaryPath, *verbose)
-	for ; err != nil && autoSize && config.Size < max_puzzle_size; config.Size = config.Size + 1 {
-		fmt.Printf("Generating puzzle of size %d\n", config.Size)
-		p, err = puzzle.GeneratePuzzle(config.Size, config.Words, config.Columns, config.Difficulty, *dictionaryPath, *verbose)
-	}
-	if err != nil {
-		fmt.Printf("Error: Failed to generate puzzle: %v\n", err)
-		os.Exit(1)
-	}
+}
 
+// writePuzzle prints the puzzle, saves it as text and writes it in the output format,
+// naming the files after config.OutputBasename.  It exits if any file cannot be written.
+func writePuzzle(config *puzzle.PuzzleConfig, p puzzle.Puzzle, format string, dpi float64, interactive bool) {
 	fmt.Println(config.Title)
 	puzzle.PrintPuzzle(p)
+	fmt.Printf("Seed: %d\n", p.Seed())
 	outputFilename := config.OutputBasename + ".txt"
-	err = puzzle.SavePuzzleToFile(p, outputFilename, true)
+	err := puzzle.SavePuzzleToFile(p, outputFilename, true)
 	if err != nil {
 		fmt.Printf("Error: Failed to save puzzle to file: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Generate PDF
-	_, err = puzzle.GeneratePDF(p, config.Title, config.Words, config.Columns, config.OutputBasename+".pdf", config.Background)
-	if err != nil {
-		fmt.Printf("Error generating PDF: %v", err)
-		os.Exit(1)
+	words := config.DisplayWords()
+	switch format {
+	case "pdf":
+		_, err = puzzle.GeneratePDF(p, config.Title, words, config.Columns, config.OutputBasename+".pdf",
+			config.RenderOptions(), config.PageSetup)
+		if err != nil {
+			fmt.Printf("Error generating PDF: %v", err)
+			os.Exit(1)
+		}
+	case "html":
+		err = puzzle.GenerateHTML(p, config.Title, words, config.Columns, config.OutputBasename+".html", interactive)
+		if err != nil {
+			fmt.Printf("Error generating HTML: %v", err)
+			os.Exit(1)
+		}
+	case "svg":
+		err = puzzle.GenerateSVG(p, config.OutputBasename+".svg", false, config.SVG)
+		if err == nil {
+			err = puzzle.GenerateSVG(p, config.OutputBasename+"_solution.svg", true, config.SVG)
+		}
+		if err != nil {
+			fmt.Printf("Error generating SVG: %v", err)
+			os.Exit(1)
+		}
+	case "png", "jpeg", "jpg":
+		ext := "." + format
+		options := config.RenderOptions()
+		options.DPI = dpi
+		err = puzzle.GenerateImage(p, config.Title, words, config.Columns, config.PageSetup,
+			config.OutputBasename+ext, false, options)
+		if err == nil {
+			err = puzzle.GenerateImage(p, config.Title, words, config.Columns, config.PageSetup,
+				config.OutputBasename+"_solution"+ext, true, options)
+		}
+		if err != nil {
+			fmt.Printf("Error generating image: %v", err)
+			os.Exit(1)
+		}
+	case "json":
+		err = puzzle.SavePuzzleToJSONFile(p, config.OutputBasename+".json")
+		if err != nil {
+			fmt.Printf("Error generating JSON: %v", err)
+			os.Exit(1)
+		}
+	default:
+		// Formats registered by other packages
+		options := config.RenderOptions()
+		options.DPI = dpi
+		r, err := puzzle.NewRenderer(format, options)
+		if err != nil {
+			fmt.Printf("Error: %v (known formats: html, svg, json, %s)\n", err,
+				strings.Join(puzzle.RendererFormats(), ", "))
+			os.Exit(1)
+		}
+		err = puzzle.RenderPuzzle(r, p, config.Title, words, config.Columns, config.PageSetup,
+			config.OutputBasename+"."+format)
+		if err != nil {
+			fmt.Printf("Error generating %s: %v", format, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// generatePuzzle generates the puzzle described by the config.  We either generate a
+// puzzle of the specified width and height, or we start any missing dimension at the max
+// word length and keep adding 1 until the words fit.
+func generatePuzzle(config *puzzle.PuzzleConfig, dictionaryPath string, verbose bool) (puzzle.Puzzle, error) {
+	// Pick the seed up front so every attempt below uses it and the final puzzle can be
+	// reproduced by passing the same seed.
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+
+	width, height := config.Dimensions()
+	autoWidth, autoHeight := (width == 0), (height == 0)
+	autoSize := autoWidth || autoHeight
+	fmt.Printf("width=%d, height=%d, autoSize=%v\n", width, height, autoSize)
+	if autoSize {
+		// Set missing dimensions to the longest word
+		words, err := config.GridWords()
+		if err != nil {
+			return puzzle.Puzzle{}, err
+		}
+		longest := 0
+		for _, word := range words {
+			if length := utf8.RuneCountInString(word); length > longest {
+				longest = length
+			}
+		}
+		if autoWidth {
+			width = longest
+		}
+		if autoHeight {
+			height = longest
+		}
+	}
+	config.Width, config.Height = width, height
+	// Only a failure to fit the words in the grid is worth retrying with a larger grid
+	p, err := puzzle.GeneratePuzzle(config, dictionaryPath, verbose)
+	var fitErr *puzzle.FitError
+	for errors.As(err, &fitErr) && autoSize && width < max_puzzle_size && height < max_puzzle_size {
+		if autoWidth {
+			width++
+		}
+		if autoHeight {
+			height++
+		}
+		config.Width, config.Height = width, height
+		fmt.Printf("Generating puzzle of size %dx%d\n", width, height)
+		p, err = puzzle.GeneratePuzzle(config, dictionaryPath, verbose)
 	}
+	return p, err
 }
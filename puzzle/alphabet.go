@@ -0,0 +1,168 @@
+package puzzle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const latinLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// builtinAlphabets are the filler letters for each language, keyed by ISO 639-1 code.
+var builtinAlphabets = map[string]string{
+	"en": latinLetters,
+	"es": "ABCDEFGHIJKLMNÑOPQRSTUVWXYZ",
+	"de": latinLetters + "ÄÖÜ",
+	"fr": latinLetters + "ÀÂÇÉÈÊËÎÏÔÙÛ",
+	"it": latinLetters + "ÀÈÉÌÒÙ",
+	"pt": latinLetters + "ÁÂÃÀÇÉÊÍÓÔÕÚ",
+	"nl": latinLetters,
+	"sv": latinLetters + "ÅÄÖ",
+	"da": latinLetters + "ÆØÅ",
+	"no": latinLetters + "ÆØÅ",
+	"fi": latinLetters + "ÄÖ",
+	"pl": "AĄBCĆDEĘFGHIJKLŁMNŃOÓPRSŚTUWYZŹŻ",
+	"tr": "ABCÇDEFGĞHIİJKLMNOÖPRSŞTUÜVYZ",
+	"el": "ΑΒΓΔΕΖΗΘΙΚΛΜΝΞΟΠΡΣΤΥΦΧΨΩ",
+	"ru": "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ",
+	"uk": "АБВГҐДЕЄЖЗИІЇЙКЛМНОПРСТУФХЦЧШЩЬЮЯ",
+}
+
+// puzzleAlphabet returns the letters used to fill the grid.  An explicit alphabet wins
+// over a language; when neither is given the alphabet is derived from the words.
+func puzzleAlphabet(alphabet, language string, words []string) ([]rune, error) {
+	letters, err := configuredAlphabet(alphabet, language)
+	if err != nil {
+		return nil, err
+	}
+	if letters == nil {
+		return deriveAlphabet(words), nil
+	}
+	warnMissingLetters(letters, words)
+	return letters, nil
+}
+
+// configuredAlphabet returns the alphabet given in the config, either as letters or
+// as a language, or nil if there is none.
+func configuredAlphabet(alphabet, language string) ([]rune, error) {
+	if alphabet != "" {
+		return parseAlphabet(alphabet)
+	}
+	if language != "" {
+		letters, ok := builtinAlphabets[strings.ToLower(language)]
+		if !ok {
+			return nil, fmt.Errorf("unknown language %q (expected one of %s)", language,
+				strings.Join(alphabetLanguages(), ", "))
+		}
+		return []rune(letters), nil
+	}
+	return nil, nil
+}
+
+// gridAlphabet returns the letters a word keeps when it is placed in the grid: the
+// alphabet given in the config, or else A-Z, so that without an alphabet or language
+// "Café" is placed as CAFE.
+func gridAlphabet(alphabet, language string) ([]rune, error) {
+	letters, err := configuredAlphabet(alphabet, language)
+	if err != nil || letters != nil {
+		return letters, err
+	}
+	return []rune(latinLetters), nil
+}
+
+// parseAlphabet uppercases the letters of an alphabet and removes spaces and repeats.
+func parseAlphabet(alphabet string) ([]rune, error) {
+	letters := make([]rune, 0)
+	seen := make(map[rune]bool)
+	for _, r := range strings.ToUpper(alphabet) {
+		if unicode.IsSpace(r) || r == ',' || seen[r] {
+			continue
+		}
+		if !unicode.IsLetter(r) {
+			return nil, fmt.Errorf("alphabet contains %q, which is not a letter", r)
+		}
+		seen[r] = true
+		letters = append(letters, r)
+	}
+	if len(letters) < 2 {
+		return nil, fmt.Errorf("alphabet needs at least two letters")
+	}
+	return letters, nil
+}
+
+// deriveAlphabet picks the filler letters for the words: the smallest built-in
+// alphabet that has every letter of the words (A-Z for plain English words), or else
+// just the letters the words use.
+func deriveAlphabet(words []string) []rune {
+	used := wordLetters(words)
+	best := ""
+	for _, language := range alphabetLanguages() {
+		letters := builtinAlphabets[language]
+		if containsLetters(letters, used) && (best == "" || len([]rune(letters)) < len([]rune(best))) {
+			best = letters
+		}
+	}
+	if best != "" {
+		return []rune(best)
+	}
+	if len(used) < 2 {
+		return []rune(latinLetters)
+	}
+	return used
+}
+
+// wordLetters returns the distinct letters of the words, in order.
+func wordLetters(words []string) []rune {
+	letters := make([]rune, 0)
+	seen := make(map[rune]bool)
+	for _, word := range words {
+		for _, r := range normalizeWord(word) {
+			if !seen[r] {
+				seen[r] = true
+				letters = append(letters, r)
+			}
+		}
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	return letters
+}
+
+func containsLetters(alphabet string, letters []rune) bool {
+	for _, r := range letters {
+		if !strings.ContainsRune(alphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// inAlphabet reports whether every letter of the word is in the alphabet, so dictionary
+// words in another script are not used as decoys.
+func inAlphabet(word string, alphabet []rune) bool {
+	return containsLetters(string(alphabet), []rune(word))
+}
+
+// warnMissingLetters logs the letters of the words that the alphabet lacks, since a
+// letter that never appears as filler gives its word away.
+func warnMissingLetters(alphabet []rune, words []string) {
+	missing := make([]string, 0)
+	for _, r := range wordLetters(words) {
+		if !strings.ContainsRune(string(alphabet), r) {
+			missing = append(missing, string(r))
+		}
+	}
+	if len(missing) > 0 {
+		logger.Warnf("The alphabet does not include %s, which appear in the words", strings.Join(missing, ", "))
+	}
+}
+
+// alphabetLanguages returns the languages with a built-in alphabet, sorted.
+func alphabetLanguages() []string {
+	languages := make([]string, 0, len(builtinAlphabets))
+	for language := range builtinAlphabets {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
@@ -0,0 +1,69 @@
+package puzzle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAlphabet(t *testing.T) {
+	letters, err := parseAlphabet("a b c, ñ, A")
+	if err != nil {
+		t.Fatalf("parseAlphabet returned error: %v", err)
+	}
+	if string(letters) != "ABCÑ" {
+		t.Errorf("Expected ABCÑ, got %s", string(letters))
+	}
+	for _, alphabet := range []string{"ABC1", "A", ""} {
+		if _, err := parseAlphabet(alphabet); err == nil {
+			t.Errorf("Expected an error for alphabet %q", alphabet)
+		}
+	}
+}
+
+func TestPuzzleAlphabet(t *testing.T) {
+	tests := []struct {
+		alphabet string
+		language string
+		words    []string
+		expected string
+	}{
+		{"", "", []string{"APPLE", "PEAR"}, latinLetters},
+		{"", "", []string{"NIÑO", "AÑO"}, builtinAlphabets["es"]},
+		{"", "", []string{"ΑΛΦΑ", "ΒΗΤΑ"}, builtinAlphabets["el"]},
+		{"", "", []string{"ЖЁЛТЫЙ"}, builtinAlphabets["ru"]},
+		// No built-in alphabet has both Ñ and Ö, so the letters of the words are used
+		{"", "", []string{"ÑÖ", "AB"}, "ABÑÖ"},
+		{"", "DE", []string{"APPLE"}, builtinAlphabets["de"]},
+		{"xyz", "de", []string{"APPLE"}, "XYZ"},
+	}
+	for _, test := range tests {
+		letters, err := puzzleAlphabet(test.alphabet, test.language, test.words)
+		if err != nil {
+			t.Fatalf("puzzleAlphabet(%q, %q, %v) returned error: %v", test.alphabet, test.language, test.words, err)
+		}
+		if string(letters) != test.expected {
+			t.Errorf("puzzleAlphabet(%q, %q, %v) = %s, expected %s", test.alphabet, test.language, test.words,
+				string(letters), test.expected)
+		}
+	}
+
+	if _, err := puzzleAlphabet("", "klingon", nil); err == nil {
+		t.Errorf("Expected an error for an unknown language")
+	}
+}
+
+func TestGeneratePuzzleGreekFiller(t *testing.T) {
+	// The English dictionary words are not used as decoys in a Greek puzzle
+	config := &PuzzleConfig{Size: 8, Difficulty: 5, Seed: 7, Words: WordEntries([]string{"ΑΛΦΑ", "ΒΗΤΑ", "ΓΑΜΜΑ"})}
+	puzzle, err := GeneratePuzzle(config, writeTestDictionary(t), false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+	for x := range puzzle.grid {
+		for y := range puzzle.grid[x] {
+			if !strings.ContainsRune(builtinAlphabets["el"], puzzle.grid[x][y]) {
+				t.Fatalf("Expected only Greek letters, found %c at (%d,%d)", puzzle.grid[x][y], x, y)
+			}
+		}
+	}
+}
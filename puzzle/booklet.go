@@ -0,0 +1,187 @@
+package puzzle
+
+import (
+	"fmt"
+	"math"
+)
+
+// Answer key layout: solutions per row and per page, the height (in mm) of the
+// caption above each solution, and of each line listing the answers to clues.
+const (
+	answerKeyColumns       = 2
+	answerKeyRows          = 3
+	answerKeyCaptionHeight = 8
+	answerKeyGap           = 6
+	answerKeyLineHeight    = 3
+)
+
+// Table of contents layout, in mm.
+const (
+	tocHeadingHeight = 20
+	tocLineHeight    = 8
+)
+
+// BookletPuzzle is one puzzle of a booklet, with the title and word list printed on
+// its page.
+type BookletPuzzle struct {
+	Puzzle     Puzzle
+	Title      string
+	Words      []string
+	Columns    int
+	Background string
+}
+
+// GenerateBooklet writes a PDF activity book: a table of contents, one numbered page
+// per puzzle, and an answer key at the back with several solutions per page.  Every
+// page uses the same page setup and fonts; the background comes from each puzzle.
+func GenerateBooklet(title string, puzzles []BookletPuzzle, page PageSetup, options RenderOptions,
+	outputFile string) error {
+	if len(puzzles) == 0 {
+		return fmt.Errorf("a booklet needs at least one puzzle")
+	}
+	if err := page.validate(); err != nil {
+		return err
+	}
+	r := newPDFRenderer(options).(*pdfRenderer)
+	pdf := r.pdf
+	tocLayout, err := NewPageLayout(page, puzzles[0].Puzzle, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	// Work out the page numbers up front so the table of contents can list them
+	entriesPerPage := int((tocLayout.ContentHeight() - tocHeadingHeight - 10) / tocLineHeight)
+	if entriesPerPage < 1 {
+		entriesPerPage = 1
+	}
+	tocPages := (len(puzzles) + 1 + entriesPerPage - 1) / entriesPerPage
+	firstPuzzlePage := tocPages + 1
+	answerKeyPage := firstPuzzlePage + len(puzzles)
+
+	// Table of contents
+	for i := 0; i <= len(puzzles); i++ {
+		if i%entriesPerPage == 0 {
+			startPDFPage(pdf, tocLayout, "")
+			r.drawTitle(title, tocLayout)
+			if i == 0 {
+				pdf.SetFont(r.textFont, "B", messageFontSize)
+				pdf.SetXY(tocLayout.LeftMargin, tocLayout.TitleY+tocLayout.TitleHeight)
+				pdf.CellFormat(tocLayout.ContentWidth(), 10, "Contents", "", 0, "L", false, 0, "")
+			}
+			r.drawPageNumber(tocLayout)
+		}
+		entry, page := "Answer Key", answerKeyPage
+		if i < len(puzzles) {
+			entry, page = fmt.Sprintf("%d. %s", i+1, puzzles[i].Title), firstPuzzlePage+i
+		}
+		y := tocLayout.TitleY + tocHeadingHeight + float64(i%entriesPerPage)*tocLineHeight
+		r.drawContentsEntry(tocLayout, y, entry, page)
+	}
+
+	// Puzzle pages
+	for i, p := range puzzles {
+		r.background = p.Background
+		layout, err := NewPageLayout(page, p.Puzzle, len(p.Words), p.Columns)
+		if err != nil {
+			return err
+		}
+		if err := r.NewPage(layout); err != nil {
+			return err
+		}
+		r.DrawTitle(fmt.Sprintf("%d. %s", i+1, p.Title))
+		r.DrawGrid(p.Puzzle)
+		drawWordList(r, p.Puzzle, p.Words)
+		r.drawPageNumber(layout)
+	}
+
+	// Answer key
+	perPage := answerKeyColumns * answerKeyRows
+	for i, p := range puzzles {
+		if i%perPage == 0 {
+			startPDFPage(pdf, tocLayout, "")
+			r.drawTitle("Answer Key", tocLayout)
+			r.drawPageNumber(tocLayout)
+		}
+		r.drawAnswer(tocLayout, i%perPage, fmt.Sprintf("%d. %s (page %d)", i+1, p.Title, firstPuzzlePage+i),
+			p.Puzzle)
+	}
+
+	return r.Save(outputFile)
+}
+
+// drawContentsEntry prints one line of the table of contents, with a dotted leader
+// between the entry and its page number.
+func (r *pdfRenderer) drawContentsEntry(layout PageLayout, y float64, entry string, page int) {
+	pdf := r.pdf
+	pdf.SetFont(r.textFont, "", wordFontSize+1)
+	entry = r.translate(entry)
+	pageText := fmt.Sprintf("%d", page)
+	entryWidth := pdf.GetStringWidth(entry) + 2
+	pageWidth := pdf.GetStringWidth(pageText) + 2
+	dotsWidth := layout.ContentWidth() - entryWidth - pageWidth
+	dots := ""
+	for pdf.GetStringWidth(dots+" .") < dotsWidth {
+		dots += " ."
+	}
+
+	pdf.SetXY(layout.LeftMargin, y)
+	pdf.CellFormat(entryWidth, tocLineHeight, entry, "", 0, "L", false, 0, "")
+	pdf.CellFormat(dotsWidth, tocLineHeight, dots, "", 0, "R", false, 0, "")
+	pdf.CellFormat(pageWidth, tocLineHeight, pageText, "", 0, "R", false, 0, "")
+}
+
+// drawPageNumber prints the page number centered at the bottom of the page.
+func (r *pdfRenderer) drawPageNumber(layout PageLayout) {
+	pdf := r.pdf
+	pdf.SetFont(r.textFont, "", wordFontSize)
+	pdf.SetXY(layout.LeftMargin, layout.SeedY())
+	pdf.CellFormat(layout.ContentWidth(), 5, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "C", false, 0, "")
+}
+
+// drawAnswer draws a small solution grid in the given slot of an answer key page,
+// with a caption naming the puzzle, and below it the hidden message and the answer
+// to each clue, if any.
+func (r *pdfRenderer) drawAnswer(page PageLayout, slot int, caption string, puzzle Puzzle) {
+	pdf := r.pdf
+	top := page.TitleY + page.TitleHeight + 5
+	slotWidth := (page.ContentWidth() - answerKeyGap*(answerKeyColumns-1)) / answerKeyColumns
+	slotHeight := (page.SeedY() - top - answerKeyGap*answerKeyRows) / answerKeyRows
+	slotX := page.LeftMargin + float64(slot%answerKeyColumns)*(slotWidth+answerKeyGap)
+	slotY := top + float64(slot/answerKeyColumns)*(slotHeight+answerKeyGap)
+
+	pdf.SetFont(r.textFont, "B", wordFontSize)
+	pdf.SetXY(slotX, slotY)
+	pdf.CellFormat(slotWidth, answerKeyCaptionHeight, r.translate(caption), "", 0, "C", false, 0, "")
+
+	answers := clueText(puzzle.clues, true)
+	gridHeight := slotHeight - answerKeyCaptionHeight - float64(len(answers))*answerKeyLineHeight
+	if puzzle.hiddenMessage != "" {
+		gridHeight -= answerKeyCaptionHeight
+	}
+	layout := page
+	layout.CellSize = math.Min(slotWidth/float64(puzzle.Width()), gridHeight/float64(puzzle.Height()))
+	layout.GridX = slotX + (slotWidth-layout.CellSize*float64(puzzle.Width()))/2
+	layout.GridY = slotY + answerKeyCaptionHeight
+	r.drawPuzzleGrid(puzzle, layout, true)
+
+	below := layout.GridBottom(puzzle)
+	if puzzle.hiddenMessage != "" {
+		pdf.SetFont(r.textFont, "", wordFontSize)
+		pdf.SetXY(slotX, below)
+		pdf.CellFormat(slotWidth, answerKeyCaptionHeight, r.translate("Hidden message: "+puzzle.hiddenMessage), "",
+			0, "C", false, 0, "")
+		below += answerKeyCaptionHeight
+	}
+
+	if len(answers) > 0 {
+		list := layout
+		list.LeftMargin = slotX
+		list.WordListY = below
+		list.WordFontSize = minWordFontSize
+		list.WordColumns = 1
+		list.WordColumnWidth = slotWidth
+		list.WordCellHeight = answerKeyLineHeight
+		list.WordLineHeight = answerKeyLineHeight
+		r.listSearchWords(answers, list, true)
+	}
+}
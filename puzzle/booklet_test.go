@@ -0,0 +1,51 @@
+package puzzle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateBooklet(t *testing.T) {
+	var puzzles []BookletPuzzle
+	for i := 0; i < 8; i++ {
+		puzzle := createPuzzle(5, 5)
+		placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+		puzzles = append(puzzles, BookletPuzzle{Puzzle: puzzle, Title: fmt.Sprintf("Puzzle %d", i+1),
+			Words: []string{"CAT"}})
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "booklet.pdf")
+	if err := GenerateBooklet("Book", puzzles, PageSetup{}, RenderOptions{}, outputFile); err != nil {
+		t.Fatalf("GenerateBooklet returned error: %v", err)
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read booklet: %v", err)
+	}
+	// One contents page, a page per puzzle and two answer key pages of six solutions
+	if !strings.Contains(string(data), "/Count 11") {
+		t.Errorf("Expected an 11 page booklet")
+	}
+}
+
+func TestGenerateBookletEmpty(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "booklet.pdf")
+	if err := GenerateBooklet("Book", nil, PageSetup{}, RenderOptions{}, outputFile); err == nil {
+		t.Errorf("Expected an error for a booklet without puzzles")
+	}
+}
+
+func TestGenerateBookletMargins(t *testing.T) {
+	puzzle := createPuzzle(5, 5)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	puzzles := []BookletPuzzle{{Puzzle: puzzle, Title: "Puzzle", Words: []string{"CAT"}}}
+
+	outputFile := filepath.Join(t.TempDir(), "booklet.pdf")
+	page := PageSetup{Margins: &Margins{Top: 100, Bottom: 100, Left: 20, Right: 20}}
+	if err := GenerateBooklet("Book", puzzles, page, RenderOptions{}, outputFile); err == nil {
+		t.Errorf("Expected an error for margins that leave no room for the contents")
+	}
+}
@@ -1,20 +1,85 @@
 package puzzle
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
 
 	"gopkg.in/yaml.v3"
 )
 
 type PuzzleConfig struct {
-	Title          string   `yaml:"title"`
-	Size           int      `yaml:"size"`
-	Columns        int      `yaml:"columns"`
-	Difficulty     int      `yaml:"difficulty"`
-	Words          []string `yaml:"words"`
-	OutputBasename string   `yaml:"output_basename"`
-	Background     string   `yaml:"background"`
+	Title           string       `yaml:"title"`
+	Size            int          `yaml:"size"`
+	Width           int          `yaml:"width"`
+	Height          int          `yaml:"height"`
+	Columns         int          `yaml:"columns"`
+	Difficulty      int          `yaml:"difficulty"`
+	Directions      []string     `yaml:"directions"`
+	Words           []WordEntry  `yaml:"words"`
+	WordsFile       string       `yaml:"words_file"`
+	Include         []WordSource `yaml:"include"`
+	OutputBasename  string       `yaml:"output_basename"`
+	Background      string       `yaml:"background"`
+	Font            string       `yaml:"font"`
+	GridFont        string       `yaml:"grid_font"`
+	Shape           string       `yaml:"shape"`
+	HiddenMessage   string       `yaml:"hidden_message"`
+	Alphabet        string       `yaml:"alphabet"`
+	Language        string       `yaml:"language"`
+	Fill            string       `yaml:"fill"`
+	FillCharacter   string       `yaml:"fill_character"`
+	Seed            int64        `yaml:"seed"`
+	MaxAttempts     int          `yaml:"max_attempts"`
+	AllowDuplicates bool         `yaml:"allow_duplicates"`
+	SVG             SVGOptions   `yaml:"svg"`
+	PageSetup       `yaml:",inline"`
+}
+
+// Dimensions returns the width and height of the puzzle grid.  Width and height
+// default to size when they are not set.
+func (c *PuzzleConfig) Dimensions() (int, int) {
+	width, height := c.Width, c.Height
+	if width == 0 {
+		width = c.Size
+	}
+	if height == 0 {
+		height = c.Size
+	}
+	return width, height
+}
+
+// DisplayWords returns the words as they are shown in the word list.
+func (c *PuzzleConfig) DisplayWords() []string {
+	words := make([]string, 0, len(c.Words))
+	for _, entry := range c.Words {
+		words = append(words, entry.DisplayText())
+	}
+	return words
+}
+
+// GridWords returns the words as they are placed in the grid, keeping the letters of
+// the alphabet or language given in the config.
+func (c *PuzzleConfig) GridWords() ([]string, error) {
+	alphabet, err := gridAlphabet(c.Alphabet, c.Language)
+	if err != nil {
+		return nil, err
+	}
+	words := make([]string, 0, len(c.Words))
+	for _, entry := range c.Words {
+		words = append(words, entry.GridWord(alphabet))
+	}
+	return words, nil
+}
+
+// RenderOptions returns the renderer settings given in the config: the background
+// image and fonts.
+func (c *PuzzleConfig) RenderOptions() RenderOptions {
+	return RenderOptions{Background: c.Background, Font: c.Font, GridFont: c.GridFont}
 }
 
 func basenameWithoutExt(filePath string) string {
@@ -24,21 +89,211 @@ func basenameWithoutExt(filePath string) string {
 	return base[:len(base)-len(ext)]
 }
 
+// ParseConfig reads a puzzle config from a YAML file holding a single puzzle (in any
+// of the forms ParseConfigs reads).  Unknown fields, values of the wrong type and
+// values out of range are errors; when there are any, the error is a ConfigErrors
+// listing each one with its line and column.
 func ParseConfig(filename string) (*PuzzleConfig, error) {
-	data, err := ioutil.ReadFile(filename)
+	configs, err := ParseConfigs(filename)
 	if err != nil {
 		return nil, err
 	}
+	if len(configs) != 1 {
+		return nil, fmt.Errorf("%s has %d puzzles, expected one", filename, len(configs))
+	}
+	return configs[0], nil
+}
 
-	var config PuzzleConfig
-	err = yaml.Unmarshal(data, &config)
+// ParseConfigs reads every puzzle config in a YAML file.  Each document of the file
+// (documents are separated by ---) is a single puzzle, a list of puzzles, or a
+// mapping with a list of puzzles under puzzles and settings they share under
+// defaults.  A document with only defaults sets them for the documents after it.
+// A puzzle's own settings override the defaults.
+//
+// Puzzles without their own output_basename are named after the YAML file (or the
+// output_basename in the defaults), numbered from 1 when the file has several.
+func ParseConfigs(filename string) ([]*PuzzleConfig, error) {
+	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	// If no output_basename provided, use the basename of the input YAML file.
-	if len(config.OutputBasename) == 0 {
-		config.OutputBasename = basenameWithoutExt(filename)
+
+	var configs []*PuzzleConfig
+	var named []bool
+	var errs ConfigErrors
+	addPuzzle := func(node, defaults *yaml.Node) error {
+		config, err := decodeConfig(mergeMappings(defaults, node), filepath.Dir(filename))
+		var configErrors ConfigErrors
+		switch {
+		case errors.As(err, &configErrors):
+			errs = appendNew(errs, configErrors)
+		case err != nil:
+			return err
+		default:
+			configs = append(configs, config)
+			named = append(named, mappingValue(node, "output_basename") != nil)
+		}
+		return nil
+	}
+
+	var streamDefaults *yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		node := document.Content[0]
+		switch {
+		case node.ShortTag() == "!!null":
+			// An empty document
+		case node.Kind == yaml.SequenceNode:
+			for _, item := range node.Content {
+				if err := addPuzzle(item, streamDefaults); err != nil {
+					return nil, err
+				}
+			}
+		case node.Kind == yaml.MappingNode && (mappingValue(node, "puzzles") != nil || mappingValue(node, "defaults") != nil):
+			defaults, puzzles, collectionErrs := puzzleCollection(node)
+			if len(collectionErrs) > 0 {
+				errs = appendNew(errs, collectionErrs)
+				continue
+			}
+			defaults = mergeMappings(streamDefaults, defaults)
+			if puzzles == nil {
+				streamDefaults = defaults
+				continue
+			}
+			for _, item := range puzzles.Content {
+				if err := addPuzzle(item, defaults); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			if err := addPuzzle(node, streamDefaults); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(errs) > 0 {
+		errs.sort()
+		return nil, errs
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("%s has no puzzles", filename)
 	}
 
+	for i, config := range configs {
+		if named[i] {
+			continue
+		}
+		// If no output_basename provided, use the basename of the input YAML file.
+		if len(config.OutputBasename) == 0 {
+			config.OutputBasename = basenameWithoutExt(filename)
+		}
+		if len(configs) > 1 {
+			config.OutputBasename = fmt.Sprintf("%s_%d", config.OutputBasename, i+1)
+		}
+	}
+	return configs, nil
+}
+
+// puzzleCollection returns the defaults and puzzles of a document that lists several
+// puzzles.  Either may be nil.
+func puzzleCollection(node *yaml.Node) (*yaml.Node, *yaml.Node, ConfigErrors) {
+	var errs ConfigErrors
+	fields := map[string]reflect.Type{"defaults": nil, "puzzles": nil}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if _, ok := fields[key.Value]; !ok {
+			errs.add(key, "unknown field %q%s", key.Value, suggestField(key.Value, fields))
+		}
+	}
+	defaults, puzzles := mappingValue(node, "defaults"), mappingValue(node, "puzzles")
+	if defaults != nil && defaults.ShortTag() == "!!null" {
+		defaults = nil
+	}
+	if defaults != nil {
+		checkFields(defaults, reflect.TypeOf(PuzzleConfig{}), "defaults", &errs)
+	}
+	if puzzles != nil && puzzles.Kind != yaml.SequenceNode {
+		errs.add(puzzles, "puzzles must be a list")
+	}
+	return defaults, puzzles, errs
+}
+
+// mergeMappings returns a mapping node with the keys of node, and those keys of
+// defaults that node does not have.  Values are not merged: a puzzle that sets svg
+// replaces all of the default svg settings.
+func mergeMappings(defaults, node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return defaults
+	}
+	if defaults == nil || node.Kind != yaml.MappingNode {
+		return node
+	}
+	merged := *node
+	merged.Content = nil
+	for i := 0; i+1 < len(defaults.Content); i += 2 {
+		if mappingValue(node, defaults.Content[i].Value) == nil {
+			merged.Content = append(merged.Content, defaults.Content[i], defaults.Content[i+1])
+		}
+	}
+	merged.Content = append(merged.Content, node.Content...)
+	return &merged
+}
+
+// appendNew appends the errors that are not already in errs.  A mistake in shared
+// defaults is found once for every puzzle, but only reported once.
+func appendNew(errs, more ConfigErrors) ConfigErrors {
+	for _, err := range more {
+		found := false
+		for _, existing := range errs {
+			if *existing == *err {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// decodeConfig decodes a YAML document into a config, checking it strictly, and reads
+// the words of any word files, which are relative to dir.
+func decodeConfig(root *yaml.Node, dir string) (*PuzzleConfig, error) {
+	node := root
+	if node.Kind == yaml.DocumentNode {
+		node = node.Content[0]
+	}
+	if node.Kind == 0 {
+		return nil, fmt.Errorf("the config is empty")
+	}
+
+	var errs ConfigErrors
+	checkFields(node, reflect.TypeOf(PuzzleConfig{}), "the config", &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var config PuzzleConfig
+	if err := node.Decode(&config); err != nil {
+		var configError *ConfigError
+		if errors.As(err, &configError) {
+			return nil, ConfigErrors{configError}
+		}
+		return nil, err
+	}
+	if errs := config.readWordSources(node, dir); len(errs) > 0 {
+		return nil, errs
+	}
+	if errs := config.checkValues(node); len(errs) > 0 {
+		return nil, errs
+	}
 	return &config, nil
 }
@@ -2,6 +2,8 @@ package puzzle
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -51,7 +53,7 @@ output_basename: "sample_output"
 	if len(config.Words) != len(expectedWords) {
 		t.Fatalf("Expected %d words, got %d", len(expectedWords), len(config.Words))
 	}
-	for i, word := range config.Words {
+	for i, word := range config.DisplayWords() {
 		if word != expectedWords[i] {
 			t.Errorf("Expected word %d to be %q, got %q", i, expectedWords[i], word)
 		}
@@ -99,3 +101,117 @@ words:
 		t.Errorf("Expected size to be %d, got %d", 0, config.Size)
 	}
 }
+
+func TestConfigDimensions(t *testing.T) {
+	testCases := []struct {
+		config        PuzzleConfig
+		width, height int
+	}{
+		{PuzzleConfig{Size: 12}, 12, 12},
+		{PuzzleConfig{Width: 20, Height: 10}, 20, 10},
+		{PuzzleConfig{Size: 12, Height: 8}, 12, 8},
+		{PuzzleConfig{}, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		width, height := tc.config.Dimensions()
+		if width != tc.width || height != tc.height {
+			t.Errorf("Expected dimensions %dx%d for %+v, got %dx%d", tc.width, tc.height, tc.config, width, height)
+		}
+	}
+}
+
+func TestParseConfigs(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		titles    []string
+		levels    []int
+		basenames []string
+	}{
+		{"single", "title: One\ndifficulty: 2\nwords: [cat]\n",
+			[]string{"One"}, []int{2}, []string{"single"}},
+		{"list", `
+- {title: One, difficulty: 2, words: [cat]}
+- {title: Two, difficulty: 3, words: [dog]}
+`, []string{"One", "Two"}, []int{2, 3}, []string{"list_1", "list_2"}},
+		{"unit", `
+defaults:
+  difficulty: 4
+  output_basename: vocab
+puzzles:
+  - {title: One, words: [cat]}
+  - {title: Two, difficulty: 8, words: [dog]}
+  - {title: Three, output_basename: three, words: [owl]}
+`, []string{"One", "Two", "Three"}, []int{4, 8, 4}, []string{"vocab_1", "vocab_2", "three"}},
+		{"stream", `
+defaults:
+  difficulty: 5
+---
+title: One
+words: [cat]
+---
+puzzles:
+  - {title: Two, words: [dog]}
+`, []string{"One", "Two"}, []int{5, 5}, []string{"stream_1", "stream_2"}},
+	}
+
+	for _, tc := range testCases {
+		configFile := filepath.Join(t.TempDir(), tc.name+".yaml")
+		if err := os.WriteFile(configFile, []byte(tc.content), 0644); err != nil {
+			t.Fatalf("Failed to write test config file: %v", err)
+		}
+		configs, err := ParseConfigs(configFile)
+		if err != nil {
+			t.Fatalf("%s: ParseConfigs returned error: %v", tc.name, err)
+		}
+		if len(configs) != len(tc.titles) {
+			t.Fatalf("%s: Expected %d configs, got %d", tc.name, len(tc.titles), len(configs))
+		}
+		for i, config := range configs {
+			if config.Title != tc.titles[i] || config.Difficulty != tc.levels[i] ||
+				config.OutputBasename != tc.basenames[i] {
+				t.Errorf("%s: Expected puzzle %d to be %q, difficulty %d, %q, got %q, %d, %q", tc.name, i,
+					tc.titles[i], tc.levels[i], tc.basenames[i], config.Title, config.Difficulty, config.OutputBasename)
+			}
+		}
+
+		_, err = ParseConfig(configFile)
+		if (err == nil) != (len(configs) == 1) {
+			t.Errorf("%s: Expected ParseConfig to accept only a single puzzle, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestParseConfigWordSources(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "banks"), 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	files := map[string]string{
+		"banks/fruit.txt": "apple\npear\nPlum\n",
+		"banks/more.csv":  "Name,Colour\nKiwi,green\nApple,red\n",
+		"fruit.yaml": `title: Fruit
+difficulty: 3
+words: [plum, fig]
+words_file: banks/fruit.txt
+include:
+  - file: banks/more.csv
+    column: Name
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file %s: %v", name, err)
+		}
+	}
+
+	config, err := ParseConfig(filepath.Join(dir, "fruit.yaml"))
+	if err != nil {
+		t.Fatalf("ParseConfig returned error: %v", err)
+	}
+	expected := []string{"plum", "fig", "apple", "pear", "Kiwi"}
+	if words := config.DisplayWords(); !reflect.DeepEqual(words, expected) {
+		t.Errorf("Expected words %q, got %q", expected, words)
+	}
+}
@@ -40,8 +40,13 @@ func LoadDictionary(dictionaryPath string) (*Dictionary, error) {
 	return &Dictionary{words: words}, nil
 }
 
-func (d *Dictionary) RandomWord() string {
-	index := rand.Intn(len(d.words))
+// Words returns every word in the dictionary.
+func (d *Dictionary) Words() []string {
+	return d.words
+}
+
+func (d *Dictionary) RandomWord(rng *rand.Rand) string {
+	index := rng.Intn(len(d.words))
 	return d.words[index]
 }
 
@@ -86,10 +91,10 @@ func removeDuplicatesAndOriginalWord(words []string, originalWord string) []stri
 	return uniqueWords
 }
 
-func (d *Dictionary) RandomWords(count int) []string {
+func (d *Dictionary) RandomWords(rng *rand.Rand, count int) []string {
 	randomWords := make([]string, 0, count)
 	for i := 0; i < count; i++ {
-		randomWords = append(randomWords, d.RandomWord())
+		randomWords = append(randomWords, d.RandomWord(rng))
 	}
 	return randomWords
 }
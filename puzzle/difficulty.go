@@ -4,11 +4,11 @@ import (
 	"math/rand"
 )
 
-func adjustWordsForDifficulty(words []string, difficulty int, dictionary *Dictionary) []string {
+func adjustWordsForDifficulty(words []string, difficulty int, dictionary *Dictionary, rng *rand.Rand) []string {
 	adjustedWords := make([]string, 0, len(words))
 
 	for _, word := range words {
-		if shouldReverseWord(difficulty) {
+		if shouldReverseWord(difficulty, rng) {
 			adjustedWords = append(adjustedWords, reverseWord(word))
 		} else {
 			adjustedWords = append(adjustedWords, word)
@@ -18,8 +18,8 @@ func adjustWordsForDifficulty(words []string, difficulty int, dictionary *Dictio
 	return adjustedWords
 }
 
-func shouldReverseWord(difficulty int) bool {
-	return rand.Intn(10) < difficulty
+func shouldReverseWord(difficulty int, rng *rand.Rand) bool {
+	return rng.Intn(10) < difficulty
 }
 
 func reverseWord(word string) string {
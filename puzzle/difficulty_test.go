@@ -1,6 +1,7 @@
 package puzzle
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -9,7 +10,7 @@ func TestAdjustWordsForDifficulty(t *testing.T) {
 	difficulty := 5
 	dictionary := &Dictionary{}
 
-	adjustedWords := adjustWordsForDifficulty(words, difficulty, dictionary)
+	adjustedWords := adjustWordsForDifficulty(words, difficulty, dictionary, rand.New(rand.NewSource(1)))
 
 	if len(adjustedWords) != len(words) {
 		t.Errorf("Expected length of adjustedWords to be %d, got %d", len(words), len(adjustedWords))
@@ -19,9 +20,10 @@ func TestAdjustWordsForDifficulty(t *testing.T) {
 func TestShouldReverseWord(t *testing.T) {
 	difficulty := 5
 	reversed := 0
+	rng := rand.New(rand.NewSource(1))
 
 	for i := 0; i < 1000; i++ {
-		if shouldReverseWord(difficulty) {
+		if shouldReverseWord(difficulty, rng) {
 			reversed++
 		}
 	}
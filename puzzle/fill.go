@@ -0,0 +1,182 @@
+package puzzle
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filler picks the letters for the cells that are not part of any word.
+type Filler interface {
+	Letter(rng *rand.Rand) rune
+}
+
+// FillerOptions is what a filler can draw on when it is created for a puzzle.
+type FillerOptions struct {
+	Alphabet   []rune      // letters the grid is filled from
+	Words      []string    // the search words, normalized
+	Dictionary *Dictionary // the dictionary used for decoy words
+	Character  rune        // the fill_character from the config, or 0
+}
+
+// FillerFactory creates a filler for one puzzle.
+type FillerFactory func(options FillerOptions) (Filler, error)
+
+// Built-in fill strategies.
+const (
+	fillUniform       = "uniform"        // every letter of the alphabet equally likely
+	fillFrequency     = "frequency"      // weighted by how often letters appear in the dictionary
+	fillWordFrequency = "word-frequency" // weighted by how often letters appear in the search words
+	fillWordLetters   = "word-letters"   // only letters that appear in the search words
+	fillFixed         = "fixed"          // the same character everywhere
+)
+
+const defaultFillCharacter = '-'
+
+var fillers = map[string]FillerFactory{
+	fillUniform: func(options FillerOptions) (Filler, error) {
+		return uniformWeights(options.Alphabet), nil
+	},
+	fillFrequency: func(options FillerOptions) (Filler, error) {
+		if options.Dictionary == nil {
+			return nil, fmt.Errorf("fill %q needs a dictionary", fillFrequency)
+		}
+		return frequencyWeights(options.Alphabet, options.Dictionary.Words()), nil
+	},
+	fillWordFrequency: func(options FillerOptions) (Filler, error) {
+		return frequencyWeights(options.Alphabet, options.Words), nil
+	},
+	fillWordLetters: func(options FillerOptions) (Filler, error) {
+		letters := wordLetters(options.Words)
+		if len(letters) == 0 {
+			letters = options.Alphabet
+		}
+		return uniformWeights(letters), nil
+	},
+	fillFixed: func(options FillerOptions) (Filler, error) {
+		if options.Character == 0 {
+			return fixedFiller(defaultFillCharacter), nil
+		}
+		return fixedFiller(options.Character), nil
+	},
+}
+
+// RegisterFiller makes a fill strategy available to the fill option of the config.
+// Registering a name again replaces the earlier factory.
+func RegisterFiller(name string, factory FillerFactory) {
+	fillers[strings.ToLower(name)] = factory
+}
+
+// NewFiller creates the filler for a registered fill strategy.  An empty name means
+// uniform.  It fails if the filler has no letters to pick from, such as when the
+// alphabet is empty.
+func NewFiller(name string, options FillerOptions) (Filler, error) {
+	if name == "" {
+		name = fillUniform
+	}
+	factory, ok := fillers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown fill %q (expected one of %s)", name, strings.Join(fillerNames(), ", "))
+	}
+	filler, err := factory(options)
+	if err != nil {
+		return nil, err
+	}
+	if weights, ok := filler.(letterWeights); ok && weights.total() == 0 {
+		return nil, fmt.Errorf("fill %q has no letters to pick from", name)
+	}
+	return filler, nil
+}
+
+// fillerNames returns the registered fill strategies, sorted.
+func fillerNames() []string {
+	names := make([]string, 0, len(fillers))
+	for name := range fillers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fillCharacter returns the single character of the fill_character option, or 0 when
+// it is not set.  A letter is refused, since it would read as part of the words.
+func fillCharacter(value string) (rune, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if utf8.RuneCountInString(value) != 1 {
+		return 0, fmt.Errorf("fill_character must be a single character, got %q", value)
+	}
+	r, _ := utf8.DecodeRuneInString(value)
+	if unicode.IsLetter(r) {
+		return 0, fmt.Errorf("fill_character must not be a letter, got %q", value)
+	}
+	return r, nil
+}
+
+// fixedFiller fills every cell with the same character.
+type fixedFiller rune
+
+func (f fixedFiller) Letter(rng *rand.Rand) rune {
+	return rune(f)
+}
+
+// letterWeights picks filler letters with a probability proportional to the weight
+// of each letter.
+type letterWeights struct {
+	letters    []rune
+	cumulative []int
+}
+
+// uniformWeights gives every letter of the alphabet the same weight.
+func uniformWeights(alphabet []rune) letterWeights {
+	weights := make([]int, len(alphabet))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return newLetterWeights(alphabet, weights)
+}
+
+// frequencyWeights weights each letter of the alphabet by how often it appears in the
+// words.  Every letter gets one extra count so that letters missing from the words
+// still turn up now and then.
+func frequencyWeights(alphabet []rune, words []string) letterWeights {
+	counts := make(map[rune]int)
+	for _, word := range words {
+		for _, r := range normalizeWord(word) {
+			counts[r]++
+		}
+	}
+	weights := make([]int, len(alphabet))
+	for i, r := range alphabet {
+		weights[i] = counts[r] + 1
+	}
+	return newLetterWeights(alphabet, weights)
+}
+
+func newLetterWeights(letters []rune, weights []int) letterWeights {
+	cumulative := make([]int, len(weights))
+	total := 0
+	for i, weight := range weights {
+		total += weight
+		cumulative[i] = total
+	}
+	return letterWeights{letters: letters, cumulative: cumulative}
+}
+
+// total returns the sum of the weights, 0 when there are no letters.
+func (w letterWeights) total() int {
+	if len(w.cumulative) == 0 {
+		return 0
+	}
+	return w.cumulative[len(w.cumulative)-1]
+}
+
+// Letter returns a letter chosen according to the weights.
+func (w letterWeights) Letter(rng *rand.Rand) rune {
+	n := rng.Intn(w.total())
+	return w.letters[sort.SearchInts(w.cumulative, n+1)]
+}
@@ -0,0 +1,119 @@
+package puzzle
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUniformWeights(t *testing.T) {
+	alphabet := []rune(builtinAlphabets["el"])
+	filler := uniformWeights(alphabet)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if r := filler.Letter(rng); !strings.ContainsRune(string(alphabet), r) {
+			t.Fatalf("randomLetter returned %c, which is not in the alphabet", r)
+		}
+	}
+}
+
+func TestFrequencyWeights(t *testing.T) {
+	filler := frequencyWeights([]rune("ABQ"), []string{"AAAA", "ABBA", "BAA"})
+	counts := make(map[rune]int)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		counts[filler.Letter(rng)]++
+	}
+	// Weights are A: 8+1, B: 3+1 and Q: 0+1
+	if counts['A'] <= counts['B'] || counts['B'] <= counts['Q'] {
+		t.Errorf("Expected A to be more common than B, and B more than Q, got %v", counts)
+	}
+	if counts['Q'] == 0 {
+		t.Errorf("Expected letters missing from the words to still appear")
+	}
+}
+
+func TestNewFiller(t *testing.T) {
+	options := FillerOptions{Alphabet: []rune(latinLetters), Words: []string{"CAT"},
+		Dictionary: &Dictionary{words: []string{"ZZZ"}}}
+	for _, name := range []string{"", "uniform", "Frequency", "word-frequency", "word-letters", "fixed"} {
+		if _, err := NewFiller(name, options); err != nil {
+			t.Errorf("NewFiller(%q) returned error: %v", name, err)
+		}
+	}
+
+	filler, _ := NewFiller("frequency", options)
+	if weights := filler.(letterWeights); weights.cumulative[25]-weights.cumulative[24] != 4 {
+		t.Errorf("Expected Z to be weighted by the dictionary")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	filler, _ = NewFiller("word-letters", options)
+	for i := 0; i < 100; i++ {
+		if r := filler.Letter(rng); !strings.ContainsRune("CAT", r) {
+			t.Fatalf("Expected only letters of the search words, got %c", r)
+		}
+	}
+
+	options.Character = '.'
+	filler, _ = NewFiller("fixed", options)
+	if r := filler.Letter(rng); r != '.' {
+		t.Errorf("Expected the fixed filler to use '.', got %c", r)
+	}
+
+	options.Dictionary = nil
+	if _, err := NewFiller("frequency", options); err == nil {
+		t.Errorf("Expected an error for frequency fill without a dictionary")
+	}
+	if _, err := NewFiller("random", options); err == nil {
+		t.Errorf("Expected an error for an unknown fill")
+	}
+	for _, name := range []string{"", "word-frequency", "word-letters"} {
+		if _, err := NewFiller(name, FillerOptions{}); err == nil {
+			t.Errorf("Expected an error for fill %q without an alphabet", name)
+		}
+	}
+}
+
+// vowelFiller is a custom filler that only uses vowels.
+type vowelFiller struct{}
+
+func (vowelFiller) Letter(rng *rand.Rand) rune {
+	return rune("AEIOU"[rng.Intn(5)])
+}
+
+func TestRegisterFiller(t *testing.T) {
+	RegisterFiller("Vowels", func(options FillerOptions) (Filler, error) { return vowelFiller{}, nil })
+	defer delete(fillers, "vowels")
+
+	filler, err := NewFiller("vowels", FillerOptions{})
+	if err != nil {
+		t.Fatalf("NewFiller returned error: %v", err)
+	}
+	grid := createEmptyGrid(4, 4)
+	if err := fillEmptyCells(grid, filler, rand.New(rand.NewSource(1))); err != nil {
+		t.Fatalf("fillEmptyCells returned error: %v", err)
+	}
+	for x := range grid {
+		for y := range grid[x] {
+			if !strings.ContainsRune("AEIOU", grid[x][y]) {
+				t.Fatalf("Expected a vowel at (%d,%d), got %c", x, y, grid[x][y])
+			}
+		}
+	}
+}
+
+func TestFillCharacter(t *testing.T) {
+	if r, err := fillCharacter("·"); err != nil || r != '·' {
+		t.Errorf("fillCharacter(\"·\") = %c, %v", r, err)
+	}
+	if r, err := fillCharacter(""); err != nil || r != 0 {
+		t.Errorf("fillCharacter(\"\") = %c, %v", r, err)
+	}
+	if _, err := fillCharacter("ab"); err == nil {
+		t.Errorf("Expected an error for more than one character")
+	}
+	if _, err := fillCharacter("X"); err == nil {
+		t.Errorf("Expected an error for a letter")
+	}
+}
@@ -0,0 +1,255 @@
+package puzzle
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+)
+
+// htmlCell is one cell of the grid in the HTML template.
+type htmlCell struct {
+	Letter     string
+	Masked     bool
+	InSolution bool
+}
+
+// htmlWord is one entry of the word list.  Key is the form of the word placed in the
+// grid, used by the script to cross words off the list.
+type htmlWord struct {
+	Display string
+	Key     string
+}
+
+type htmlPage struct {
+	Title         string
+	Rows          [][]htmlCell
+	Words         []htmlWord
+	Columns       int
+	Interactive   bool
+	PlacedWords   []placedWordJSON
+	Answers       []string
+	HiddenMessage string
+	Seed          int64
+}
+
+// GenerateHTML writes a single self-contained HTML file with the puzzle grid, the
+// word list and a solution view that can be shown or printed.  When interactive is
+// true the page includes a script that lets players select words by dragging across
+// the grid and checks each selection against the placed words.  A puzzle with clues
+// lists them, numbered, in place of the words, and its solution gives the answers.
+func GenerateHTML(puzzle Puzzle, title string, words []string, columns int, outputFile string,
+	interactive bool) error {
+	if columns < 1 {
+		columns = 5
+	}
+	page := htmlPage{
+		Title:         title,
+		Columns:       columns,
+		Interactive:   interactive,
+		PlacedWords:   make([]placedWordJSON, 0, len(puzzle.placedWords)),
+		HiddenMessage: puzzle.hiddenMessage,
+		Seed:          puzzle.seed,
+	}
+	for y := 0; y < puzzle.Height(); y++ {
+		row := make([]htmlCell, 0, puzzle.Width())
+		for x := 0; x < puzzle.Width(); x++ {
+			row = append(row, htmlCell{
+				Letter:     cellText(puzzle.grid[x][y]),
+				Masked:     isMaskedCell(puzzle.grid, x, y),
+				InSolution: puzzle.solution[x][y] != ' ' && !isMaskedCell(puzzle.solution, x, y),
+			})
+		}
+		page.Rows = append(page.Rows, row)
+	}
+	if clues := puzzle.Clues(); len(clues) > 0 {
+		for i, clue := range clues {
+			page.Words = append(page.Words, htmlWord{Display: fmt.Sprintf("%d. %s", i+1, clue.Text),
+				Key: puzzle.gridWord(clue.Answer)})
+		}
+		page.Answers = clueText(clues, true)
+	} else {
+		for _, word := range words {
+			page.Words = append(page.Words, htmlWord{Display: word, Key: puzzle.gridWord(word)})
+		}
+	}
+	for _, placedWord := range puzzle.placedWords {
+		page.PlacedWords = append(page.PlacedWords, placedWordJSON{Word: placedWord.word,
+			X: placedWord.x, Y: placedWord.y, DX: placedWord.dx, DY: placedWord.dy})
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return htmlTemplate.Execute(file, page)
+}
+
+var htmlTemplate = template.Must(template.New("puzzle").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Arial, Helvetica, sans-serif; margin: 2em auto; max-width: 60em; text-align: center; }
+h1 { margin-bottom: 0.5em; }
+table.grid { border-collapse: collapse; margin: 0 auto; user-select: none; -webkit-user-select: none; touch-action: none; }
+table.grid td { width: 1.8em; height: 1.8em; font-family: "Courier New", Courier, monospace; font-weight: bold;
+  font-size: 1.3em; text-align: center; vertical-align: middle; }
+table.grid td.masked { visibility: hidden; }
+table.grid td.selecting { background: #ffe082; }
+table.grid td.found { background: #a5d6a7; }
+ul.words { list-style: none; padding: 0; margin: 1.5em auto; column-count: {{.Columns}}; }
+ul.words li { padding: 0.2em 0; }
+ul.words li.found { text-decoration: line-through; color: #888; }
+ol.answers { display: inline-block; text-align: left; }
+#controls { margin: 1em; }
+#done { font-weight: bold; color: #2e7d32; }
+#solution { display: none; }
+#solution td { color: #ccc; }
+#solution td.answer { color: #000; background: #e0e0e0; }
+body.show-solution #solution { display: block; }
+.seed { font-size: 0.7em; color: #888; margin-top: 2em; }
+@media print {
+  #controls, #done { display: none; }
+  #solution { page-break-before: always; }
+  table.grid td.selecting, table.grid td.found { background: none; }
+}
+</style>
+</head>
+<body>
+<section id="puzzle">
+<h1>{{.Title}}</h1>
+<table class="grid" id="grid">
+{{- range $y, $row := .Rows}}
+<tr>{{range $x, $cell := $row}}<td data-x="{{$x}}" data-y="{{$y}}"{{if $cell.Masked}} class="masked"{{end}}>{{$cell.Letter}}</td>{{end}}</tr>
+{{- end}}
+</table>
+<ul class="words">
+{{- range .Words}}
+<li data-word="{{.Key}}">{{.Display}}</li>
+{{- end}}
+</ul>
+<p id="done" hidden>You found all the words!</p>
+<div id="controls">
+<button type="button" onclick="document.body.classList.toggle('show-solution')">Show solution</button>
+<button type="button" onclick="document.body.classList.add('show-solution'); window.print()">Print with solution</button>
+</div>
+<p class="seed">Seed: {{.Seed}}</p>
+</section>
+<section id="solution">
+<h1>{{.Title}} &ndash; Solution</h1>
+<table class="grid">
+{{- range .Rows}}
+<tr>{{range .}}<td class="{{if .Masked}}masked{{else if .InSolution}}answer{{end}}">{{.Letter}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- if .Answers}}
+<ol class="answers">
+{{- range .Answers}}
+<li>{{.}}</li>
+{{- end}}
+</ol>
+{{- end}}
+{{- if .HiddenMessage}}
+<p><strong>Hidden message: {{.HiddenMessage}}</strong></p>
+{{- end}}
+</section>
+{{- if .Interactive}}
+<script>
+(function () {
+  var placedWords = {{.PlacedWords}};
+  var grid = document.getElementById("grid");
+  var start = null;
+  var selected = [];
+  var found = 0;
+
+  function cellAt(x, y) {
+    return grid.querySelector('td[data-x="' + x + '"][data-y="' + y + '"]');
+  }
+
+  function cellFromEvent(event) {
+    var el = document.elementFromPoint(event.clientX, event.clientY);
+    return el && el.tagName === "TD" && grid.contains(el) ? el : null;
+  }
+
+  // Cells on the straight line from start to end, or none if they are not in line
+  function lineCells(end) {
+    var x0 = +start.dataset.x, y0 = +start.dataset.y;
+    var x1 = +end.dataset.x, y1 = +end.dataset.y;
+    var dx = Math.sign(x1 - x0), dy = Math.sign(y1 - y0);
+    var length = Math.max(Math.abs(x1 - x0), Math.abs(y1 - y0));
+    if (x0 + dx * length !== x1 || y0 + dy * length !== y1) {
+      return [];
+    }
+    var cells = [];
+    for (var i = 0; i <= length; i++) {
+      cells.push(cellAt(x0 + dx * i, y0 + dy * i));
+    }
+    return cells;
+  }
+
+  function clearSelection() {
+    selected.forEach(function (cell) { cell.classList.remove("selecting"); });
+    selected = [];
+  }
+
+  function matches(word, cells) {
+    if (word.found || cells.length !== Array.from(word.word).length) {
+      return false;
+    }
+    var endX = word.x + word.dx * (cells.length - 1), endY = word.y + word.dy * (cells.length - 1);
+    var first = cells[0].dataset, last = cells[cells.length - 1].dataset;
+    return (+first.x === word.x && +first.y === word.y && +last.x === endX && +last.y === endY) ||
+      (+last.x === word.x && +last.y === word.y && +first.x === endX && +first.y === endY);
+  }
+
+  grid.addEventListener("pointerdown", function (event) {
+    var cell = cellFromEvent(event);
+    if (!cell || cell.classList.contains("masked")) {
+      return;
+    }
+    start = cell;
+    selected = [cell];
+    cell.classList.add("selecting");
+    event.preventDefault();
+  });
+
+  document.addEventListener("pointermove", function (event) {
+    var cell = start && cellFromEvent(event);
+    if (!cell) {
+      return;
+    }
+    clearSelection();
+    selected = lineCells(cell);
+    selected.forEach(function (c) { c.classList.add("selecting"); });
+  });
+
+  document.addEventListener("pointerup", function () {
+    if (!start) {
+      return;
+    }
+    placedWords.forEach(function (word) {
+      if (matches(word, selected)) {
+        word.found = true;
+        found++;
+        selected.forEach(function (c) { c.classList.add("found"); });
+        document.querySelectorAll('ul.words li[data-word="' + word.word + '"]').forEach(function (li) {
+          li.classList.add("found");
+        });
+      }
+    });
+    if (found === placedWords.length) {
+      document.getElementById("done").hidden = false;
+    }
+    clearSelection();
+    start = null;
+  });
+})();
+</script>
+{{- end}}
+</body>
+</html>
+`))
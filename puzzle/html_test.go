@@ -0,0 +1,52 @@
+package puzzle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateHTML(t *testing.T) {
+	puzzle := createPuzzle(4, 2)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	for x, r := range "XDOG" {
+		puzzle.grid[x][1] = r
+	}
+	puzzle.grid[3][0] = 'Q'
+
+	outputDir := t.TempDir()
+	interactiveFile := filepath.Join(outputDir, "interactive.html")
+	if err := GenerateHTML(puzzle, "Pets & Friends", []string{"cat"}, 3, interactiveFile, true); err != nil {
+		t.Fatalf("GenerateHTML returned error: %v", err)
+	}
+	data, err := os.ReadFile(interactiveFile)
+	if err != nil {
+		t.Fatalf("Failed to read HTML output: %v", err)
+	}
+	html := string(data)
+	for _, expected := range []string{
+		"<title>Pets &amp; Friends</title>",
+		`<li data-word="CAT">cat</li>`,
+		`<td data-x="3" data-y="0">Q</td>`,
+		`{"word":"CAT","x":0,"y":0,"dx":1,"dy":0}`,
+		"column-count: 3",
+		"<script>",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected HTML output to contain %q", expected)
+		}
+	}
+
+	staticFile := filepath.Join(outputDir, "static.html")
+	if err := GenerateHTML(puzzle, "Pets", []string{"cat"}, 3, staticFile, false); err != nil {
+		t.Fatalf("GenerateHTML returned error: %v", err)
+	}
+	data, err = os.ReadFile(staticFile)
+	if err != nil {
+		t.Fatalf("Failed to read HTML output: %v", err)
+	}
+	if strings.Contains(string(data), "<script>") {
+		t.Errorf("Expected no script in non-interactive HTML output")
+	}
+}
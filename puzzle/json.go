@@ -0,0 +1,165 @@
+package puzzle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// puzzleJSON is the JSON form of a Puzzle.  The grid is stored one row per string,
+// with cells outside the puzzle shape as spaces, and the words in the order they were
+// given, each with the form shown in the word list.
+type puzzleJSON struct {
+	Title         string           `json:"title"`
+	Seed          int64            `json:"seed"`
+	Width         int              `json:"width"`
+	Height        int              `json:"height"`
+	Grid          []string         `json:"grid"`
+	Words         []placedWordJSON `json:"words"`
+	HiddenMessage string           `json:"hidden_message,omitempty"`
+	Clues         []Clue           `json:"clues,omitempty"`
+}
+
+type placedWordJSON struct {
+	Word    string `json:"word"`
+	Display string `json:"display,omitempty"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	DX      int    `json:"dx"`
+	DY      int    `json:"dy"`
+}
+
+// Title returns the title of the puzzle.
+func (p Puzzle) Title() string {
+	return p.title
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p Puzzle) MarshalJSON() ([]byte, error) {
+	data := puzzleJSON{
+		Title:         p.title,
+		Seed:          p.seed,
+		Width:         p.Width(),
+		Height:        p.Height(),
+		Grid:          make([]string, 0, p.Height()),
+		Words:         make([]placedWordJSON, 0, len(p.placedWords)),
+		HiddenMessage: p.hiddenMessage,
+		Clues:         p.clues,
+	}
+	for y := 0; y < p.Height(); y++ {
+		row := make([]rune, 0, p.Width())
+		for x := 0; x < p.Width(); x++ {
+			row = append(row, []rune(cellText(p.grid[x][y]))...)
+		}
+		data.Grid = append(data.Grid, string(row))
+	}
+	written := make([]bool, len(p.placedWords))
+	add := func(i int, display string) {
+		placedWord := p.placedWords[i]
+		data.Words = append(data.Words, placedWordJSON{Word: placedWord.word, Display: display,
+			X: placedWord.x, Y: placedWord.y, DX: placedWord.dx, DY: placedWord.dy})
+		written[i] = true
+	}
+	for i, entry := range p.words {
+		if i < len(p.placements) && p.placements[i] >= 0 {
+			add(p.placements[i], entry.Display)
+		}
+	}
+	// Words placed without an entry, such as those of a puzzle loaded from older JSON
+	for i := range p.placedWords {
+		if !written[i] {
+			add(i, "")
+		}
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.  The solution is rebuilt from the placed
+// words, which must match the letters in the grid.
+func (p *Puzzle) UnmarshalJSON(b []byte) error {
+	var data puzzleJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	if data.Width <= 0 || data.Height <= 0 {
+		return fmt.Errorf("invalid grid size %dx%d", data.Width, data.Height)
+	}
+	if len(data.Grid) != data.Height {
+		return fmt.Errorf("grid has %d rows but height is %d", len(data.Grid), data.Height)
+	}
+
+	puzzle := createPuzzle(data.Width, data.Height)
+	puzzle.title = data.Title
+	puzzle.seed = data.Seed
+	for y, row := range data.Grid {
+		runes := []rune(row)
+		if len(runes) != data.Width {
+			return fmt.Errorf("grid row %d has %d cells but width is %d", y, len(runes), data.Width)
+		}
+		for x, r := range runes {
+			if r == ' ' {
+				r = maskedCell
+				puzzle.solution[x][y] = maskedCell
+			}
+			puzzle.grid[x][y] = r
+		}
+	}
+
+	for _, word := range data.Words {
+		location := WordLocation{Word: word.Word, X: word.X, Y: word.Y, DX: word.DX, DY: word.DY}
+		if word.Word == "" || !matchesAt(puzzle.grid, []rune(word.Word), word.X, word.Y, word.DX, word.DY) {
+			return fmt.Errorf("word %s is not in the grid at %s", word.Word, location)
+		}
+		for i, r := range []rune(word.Word) {
+			puzzle.solution[word.X+i*word.DX][word.Y+i*word.DY] = r
+		}
+		puzzle.placedWords = append(puzzle.placedWords, placedSearchWord{word: word.Word,
+			x: word.X, y: word.Y, dx: word.DX, dy: word.DY})
+		if word.Display != "" {
+			puzzle.words = append(puzzle.words, WordEntry{Display: word.Display, Grid: word.Word})
+			puzzle.placements = append(puzzle.placements, len(puzzle.placedWords)-1)
+		}
+	}
+
+	// The hidden message fills the first cells in reading order that are not part of
+	// a search word.
+	message := []rune(data.HiddenMessage)
+	for y := 0; y < data.Height && len(puzzle.messageCells) < len(message); y++ {
+		for x := 0; x < data.Width && len(puzzle.messageCells) < len(message); x++ {
+			if puzzle.solution[x][y] == ' ' {
+				if puzzle.grid[x][y] != message[len(puzzle.messageCells)] {
+					return fmt.Errorf("hidden message %s does not match the grid", data.HiddenMessage)
+				}
+				puzzle.messageCells = append(puzzle.messageCells, [2]int{x, y})
+			}
+		}
+	}
+	if len(puzzle.messageCells) < len(message) {
+		return fmt.Errorf("hidden message %s does not fit in the grid", data.HiddenMessage)
+	}
+	puzzle.hiddenMessage = data.HiddenMessage
+	puzzle.clues = data.Clues
+
+	*p = puzzle
+	return nil
+}
+
+// SavePuzzleToJSONFile writes the puzzle as JSON to the specified filename.
+func SavePuzzleToJSONFile(puzzle Puzzle, filename string) error {
+	data, err := json.MarshalIndent(puzzle, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// LoadPuzzleFromJSONFile reads a puzzle previously saved with SavePuzzleToJSONFile.
+func LoadPuzzleFromJSONFile(filename string) (Puzzle, error) {
+	var puzzle Puzzle
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return puzzle, err
+	}
+	err = json.Unmarshal(data, &puzzle)
+	return puzzle, err
+}
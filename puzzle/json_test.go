@@ -0,0 +1,122 @@
+package puzzle
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPuzzleJSONRoundTrip(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Title: "Fruit", Width: 9, Height: 8, Difficulty: 5, Seed: 11, Shape: "circle",
+		HiddenMessage: "Yum", Words: WordEntries([]string{"Pear", "Apple", "Kiwi Fruit", "Plum"})}
+
+	original, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	var loaded Puzzle
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+
+	if loaded.Title() != "Fruit" || loaded.Seed() != 11 || loaded.HiddenMessage() != "YUM" {
+		t.Errorf("Unexpected title, seed or hidden message: %q, %d, %q",
+			loaded.Title(), loaded.Seed(), loaded.HiddenMessage())
+	}
+	if loaded.Width() != 9 || loaded.Height() != 8 {
+		t.Fatalf("Expected a 9x8 grid, got %dx%d", loaded.Width(), loaded.Height())
+	}
+	for x := range original.grid {
+		for y := range original.grid[x] {
+			if loaded.grid[x][y] != original.grid[x][y] {
+				t.Errorf("Grid differs at (%d,%d): %q != %q", x, y, loaded.grid[x][y], original.grid[x][y])
+			}
+			if loaded.solution[x][y] != original.solution[x][y] {
+				t.Errorf("Solution differs at (%d,%d): %q != %q", x, y, loaded.solution[x][y], original.solution[x][y])
+			}
+		}
+	}
+	if len(loaded.placedWords) != len(original.placedWords) {
+		t.Fatalf("Expected %d placed words, got %d", len(original.placedWords), len(loaded.placedWords))
+	}
+	// The words are saved in the order they were given, not the order they were placed
+	loadedWords := make(map[string]placedSearchWord)
+	for _, placedWord := range loaded.placedWords {
+		loadedWords[placedWord.word] = placedWord
+	}
+	for _, placedWord := range original.placedWords {
+		if loadedWords[placedWord.word] != placedWord {
+			t.Errorf("Placed word %s differs: %v != %v", placedWord.word, loadedWords[placedWord.word], placedWord)
+		}
+	}
+	expected := []WordEntry{{Display: "Pear", Grid: "PEAR"}, {Display: "Apple", Grid: "APPLE"},
+		{Display: "Kiwi Fruit", Grid: "KIWIFRUIT"}, {Display: "Plum", Grid: "PLUM"}}
+	if entries := loaded.Entries(); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected entries %v, got %v", expected, entries)
+	}
+	if len(loaded.messageCells) != len(original.messageCells) {
+		t.Errorf("Expected %d message cells, got %d", len(original.messageCells), len(loaded.messageCells))
+	}
+}
+
+func TestPuzzleUnmarshalJSONErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"Wrong row count", `{"width": 3, "height": 2, "grid": ["CAT"]}`},
+		{"Wrong row length", `{"width": 3, "height": 1, "grid": ["CATS"]}`},
+		{"Word not in grid", `{"width": 3, "height": 1, "grid": ["CAT"], "words": [{"word": "DOG", "x": 0, "y": 0, "dx": 1, "dy": 0}]}`},
+		{"Word out of bounds", `{"width": 3, "height": 1, "grid": ["CAT"], "words": [{"word": "CAT", "x": 1, "y": 0, "dx": 1, "dy": 0}]}`},
+		{"Hidden message mismatch", `{"width": 3, "height": 1, "grid": ["CAT"], "hidden_message": "DOG"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var puzzle Puzzle
+			if err := json.Unmarshal([]byte(tc.data), &puzzle); err == nil {
+				t.Errorf("Expected an error but didn't get one")
+			}
+		})
+	}
+}
+
+func TestPuzzleJSONDuplicateGridWords(t *testing.T) {
+	// Both entries are placed as CAT, and each keeps its own placement
+	puzzle := createPuzzle(3, 3)
+	placeWord(&puzzle, "CAT", 0, 2, 1, 0, true)
+	placeWord(&puzzle, "DOG", 0, 1, 1, 0, true)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	puzzle.words = []WordEntry{{Display: "Cat", Grid: "CAT"}, {Display: "Dog", Grid: "DOG"},
+		{Display: "C.A.T.", Grid: "CAT"}}
+	puzzle.placements = []int{2, 1, 0}
+
+	data, err := json.Marshal(puzzle)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	var saved puzzleJSON
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	expected := []placedWordJSON{{Word: "CAT", Display: "Cat", X: 0, Y: 0, DX: 1, DY: 0},
+		{Word: "DOG", Display: "Dog", X: 0, Y: 1, DX: 1, DY: 0},
+		{Word: "CAT", Display: "C.A.T.", X: 0, Y: 2, DX: 1, DY: 0}}
+	if !reflect.DeepEqual(saved.Words, expected) {
+		t.Errorf("Expected saved words %v, got %v", expected, saved.Words)
+	}
+
+	var loaded Puzzle
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Entries(), puzzle.words) || !reflect.DeepEqual(loaded.placements, []int{0, 1, 2}) {
+		t.Errorf("Expected entries %v, got %v placed at %v", puzzle.words, loaded.Entries(), loaded.placements)
+	}
+}
@@ -0,0 +1,171 @@
+package puzzle
+
+import (
+	"fmt"
+	"math"
+)
+
+// Limits used when shrinking a page to fit: grid cells smaller than minCellSize (in mm)
+// are hard to read, and the word list font is never made smaller than minWordFontSize.
+const (
+	minCellSize     = 4
+	minWordFontSize = 6
+	wordListGap     = 10
+	messageHeight   = 10
+)
+
+// Font sizes (in points) used on a puzzle page.
+const (
+	titleFontSize   = 24
+	wordFontSize    = 11
+	messageFontSize = 14
+	seedFontSize    = 8
+)
+
+// PageLayout holds the position (in mm) of every part of a puzzle page, so all
+// renderers place the title, grid and word list in the same spots.
+type PageLayout struct {
+	PageWidth    float64
+	PageHeight   float64
+	LeftMargin   float64
+	TopMargin    float64
+	RightMargin  float64
+	BottomMargin float64
+
+	TitleY      float64
+	TitleHeight float64
+
+	GridX    float64
+	GridY    float64
+	CellSize float64
+
+	WordListY       float64
+	WordFontSize    float64
+	WordColumns     int
+	WordColumnWidth float64
+	WordCellHeight  float64
+	WordLineHeight  float64
+}
+
+// NewPageLayout lays out a page for the puzzle and a word list of wordCount words,
+// split into the given number of columns (by default 5, or 1 for a list of clues).
+// The height of the word list is measured first, and the grid is made as large as
+// fits both the width of the page and the height left over (centered when the height
+// is the limit).  If that makes the grid cells too small, the word list font is shrunk
+// to make more room, and a warning is logged when the grid is still hard to read.
+func NewPageLayout(page PageSetup, puzzle Puzzle, wordCount int, columns int) (PageLayout, error) {
+	pageWidth, pageHeight, err := page.PageSize()
+	if err != nil {
+		return PageLayout{}, err
+	}
+	if columns < 1 {
+		columns = 5
+		if len(puzzle.clues) > 0 {
+			columns = 1
+		}
+	}
+	margins := page.margins()
+	l := PageLayout{
+		PageWidth:    pageWidth,
+		PageHeight:   pageHeight,
+		LeftMargin:   margins.Left,
+		TopMargin:    margins.Top,
+		RightMargin:  margins.Right,
+		BottomMargin: margins.Bottom,
+		TitleHeight:  10,
+		WordColumns:  columns,
+		WordFontSize: wordFontSize,
+	}
+	l.TitleY = l.TopMargin
+	l.GridY = l.TitleY + l.TitleHeight
+	l.WordColumnWidth = l.ContentWidth() / float64(columns)
+	l.WordCellHeight = 10
+
+	rows := (wordCount + columns - 1) / columns
+	// The solution shows the hidden message on its own line below the word list (the
+	// answers to clues, or the words themselves in an image), so it is kept free.
+	reserved := 0.0
+	if puzzle.hiddenMessage != "" {
+		reserved = messageHeight
+	}
+	for {
+		l.WordLineHeight = l.WordFontSize / 2
+		wordListHeight := l.WordCellHeight
+		if rows > 1 {
+			wordListHeight += float64(rows-1) * l.WordLineHeight
+		}
+		gridHeight := l.SeedY() - l.GridY - wordListGap - wordListHeight - reserved
+		l.CellSize = math.Min(l.ContentWidth()/float64(puzzle.Width()), gridHeight/float64(puzzle.Height()))
+		if l.CellSize >= minCellSize || l.WordFontSize <= minWordFontSize {
+			break
+		}
+		l.WordFontSize--
+	}
+	if l.CellSize <= 0 {
+		return PageLayout{}, fmt.Errorf("the word list (%d words) leaves no room for the grid on the page", wordCount)
+	}
+	if l.CellSize < minCellSize {
+		logger.Warnf("Grid cells are only %.1fmm wide, the puzzle may be hard to read; "+
+			"try a larger page, smaller margins or more word list columns", l.CellSize)
+	}
+	l.GridX = l.LeftMargin + (l.ContentWidth()-l.CellSize*float64(puzzle.Width()))/2
+	l.WordListY = l.GridBottom(puzzle) + wordListGap
+	return l, nil
+}
+
+// ContentWidth is the width of the page between the margins.
+func (l PageLayout) ContentWidth() float64 {
+	return l.PageWidth - (l.LeftMargin + l.RightMargin)
+}
+
+// ContentHeight is the height of the page between the margins.
+func (l PageLayout) ContentHeight() float64 {
+	return l.PageHeight - (l.TopMargin + l.BottomMargin)
+}
+
+// GridFontSize is the font size (in points) of the grid letters.
+func (l PageLayout) GridFontSize() float64 {
+	return l.CellSize * 2.0
+}
+
+// GridBottom is the y position just below the last row of the grid.
+func (l PageLayout) GridBottom(puzzle Puzzle) float64 {
+	return l.GridY + float64(puzzle.Height())*l.CellSize
+}
+
+// CellPosition returns the top-left corner of a grid cell.
+func (l PageLayout) CellPosition(x, y int) (float64, float64) {
+	return l.GridX + float64(x)*l.CellSize, l.GridY + float64(y)*l.CellSize
+}
+
+// CellCenter returns the center of a grid cell.
+func (l PageLayout) CellCenter(x, y int) (float64, float64) {
+	left, top := l.CellPosition(x, y)
+	return left + l.CellSize/2, top + l.CellSize/2
+}
+
+// WordPosition returns the top-left corner of the i-th entry of the word list.
+func (l PageLayout) WordPosition(i int) (float64, float64) {
+	column := i % l.WordColumns
+	row := i / l.WordColumns
+	return l.LeftMargin + float64(column)*l.WordColumnWidth, l.WordListY + float64(row)*l.WordLineHeight
+}
+
+// MessageY is the top of the line the hidden message is printed on in the solution,
+// between the word list and the seed.
+func (l PageLayout) MessageY() float64 {
+	return l.SeedY() - messageHeight
+}
+
+// SeedY is the top of the line the seed is printed on.
+func (l PageLayout) SeedY() float64 {
+	return l.PageHeight - l.BottomMargin - 5
+}
+
+// WordEnds returns the centers of the first and last cells of a placed word.
+func (l PageLayout) WordEnds(location WordLocation) (float64, float64, float64, float64) {
+	length := len([]rune(location.Word))
+	startX, startY := l.CellCenter(location.X, location.Y)
+	endX, endY := l.CellCenter(location.X+location.DX*(length-1), location.Y+location.DY*(length-1))
+	return startX, startY, endX, endY
+}
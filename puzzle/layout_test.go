@@ -0,0 +1,83 @@
+package puzzle
+
+import "testing"
+
+// wordListBottom returns the bottom of the last word list entry.
+func wordListBottom(layout PageLayout, wordCount int) float64 {
+	_, y := layout.WordPosition(wordCount - 1)
+	return y + layout.WordCellHeight
+}
+
+func TestPageLayoutWordList(t *testing.T) {
+	puzzle := createPuzzle(20, 20)
+	short, err := NewPageLayout(PageSetup{}, puzzle, 5, 5)
+	if err != nil {
+		t.Fatalf("NewPageLayout returned error: %v", err)
+	}
+	long, err := NewPageLayout(PageSetup{}, puzzle, 100, 5)
+	if err != nil {
+		t.Fatalf("NewPageLayout returned error: %v", err)
+	}
+	if long.CellSize >= short.CellSize {
+		t.Errorf("Expected a long word list to shrink the grid, got %g and %g", long.CellSize, short.CellSize)
+	}
+	for _, test := range []struct {
+		layout    PageLayout
+		wordCount int
+	}{{short, 5}, {long, 100}} {
+		if bottom := wordListBottom(test.layout, test.wordCount); bottom > test.layout.SeedY()+0.001 {
+			t.Errorf("%d words: word list ends at %g, below the seed line at %g", test.wordCount, bottom,
+				test.layout.SeedY())
+		}
+	}
+}
+
+func TestPageLayoutShrinksWordFont(t *testing.T) {
+	puzzle := createPuzzle(40, 40)
+	layout, err := NewPageLayout(PageSetup{}, puzzle, 300, 5)
+	if err != nil {
+		t.Fatalf("NewPageLayout returned error: %v", err)
+	}
+	if layout.WordFontSize >= wordFontSize {
+		t.Errorf("Expected the word list font to shrink, got %g", layout.WordFontSize)
+	}
+	if bottom := wordListBottom(layout, 300); bottom > layout.SeedY()+0.001 {
+		t.Errorf("Word list ends at %g, below the seed line at %g", bottom, layout.SeedY())
+	}
+}
+
+func TestPageLayoutNoRoom(t *testing.T) {
+	puzzle := createPuzzle(10, 10)
+	if _, err := NewPageLayout(PageSetup{}, puzzle, 1000, 1); err == nil {
+		t.Errorf("Expected an error when the word list fills the page")
+	}
+}
+
+func TestPageLayoutClues(t *testing.T) {
+	puzzle := createPuzzle(10, 20)
+	puzzle.clues = make([]Clue, 20)
+	puzzle.hiddenMessage = "HI"
+	layout, err := NewPageLayout(PageSetup{}, puzzle, len(puzzle.clues), 0)
+	if err != nil {
+		t.Fatalf("NewPageLayout returned error: %v", err)
+	}
+	if layout.WordColumns != 1 {
+		t.Errorf("Expected clues in one column, got %d", layout.WordColumns)
+	}
+	if bottom := wordListBottom(layout, len(puzzle.clues)); bottom > layout.MessageY()+0.001 {
+		t.Errorf("Clues end at %g, below the hidden message at %g", bottom, layout.MessageY())
+	}
+}
+
+func TestPageLayoutHiddenMessage(t *testing.T) {
+	// Images show the word list on the solution too, so the message goes below it
+	puzzle := createPuzzle(10, 20)
+	puzzle.hiddenMessage = "HI"
+	layout, err := NewPageLayout(PageSetup{}, puzzle, 40, 5)
+	if err != nil {
+		t.Fatalf("NewPageLayout returned error: %v", err)
+	}
+	if bottom := wordListBottom(layout, 40); bottom > layout.MessageY()+0.001 {
+		t.Errorf("Word list ends at %g, below the hidden message at %g", bottom, layout.MessageY())
+	}
+}
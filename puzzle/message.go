@@ -0,0 +1,66 @@
+package puzzle
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// HiddenMessage returns the secret message spelled by the leftover letters, if any.
+func (p Puzzle) HiddenMessage() string {
+	return p.hiddenMessage
+}
+
+// normalizeMessage returns the letters of a hidden message, uppercased, with spaces
+// and punctuation removed.
+func normalizeMessage(message string) string {
+	var sb strings.Builder
+	for _, r := range message {
+		if unicode.IsLetter(r) {
+			sb.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return sb.String()
+}
+
+// placeHiddenMessage writes the message into the cells the search words left empty,
+// in reading order (left to right, top to bottom), so that the letters that are not
+// part of a search word spell the message and nothing else.  Any cells left over after
+// the message are blanked, like the cells outside a shape.  It must be called after
+// the search words are placed, and no decoy words or filler letters are added after it.
+func placeHiddenMessage(puzzle *Puzzle, message string) error {
+	letters := []rune(message)
+	cells := make([][2]int, 0, len(letters))
+	for y := 0; y < puzzle.Height(); y++ {
+		for x := 0; x < puzzle.Width(); x++ {
+			if isEmptyCell(puzzle.grid, x, y) {
+				cells = append(cells, [2]int{x, y})
+			}
+		}
+	}
+	if len(cells) < len(letters) {
+		return &FitError{Message: fmt.Sprintf("not enough room for the hidden message: it needs %d cells "+
+			"but only %d are free", len(letters), len(cells))}
+	}
+
+	for i, cell := range cells {
+		if i < len(letters) {
+			puzzle.grid[cell[0]][cell[1]] = letters[i]
+		} else {
+			puzzle.grid[cell[0]][cell[1]] = maskedCell
+			puzzle.solution[cell[0]][cell[1]] = maskedCell
+		}
+	}
+	puzzle.hiddenMessage = message
+	puzzle.messageCells = cells[:len(letters)]
+	return nil
+}
+
+func (p Puzzle) isMessageCell(x, y int) bool {
+	for _, cell := range p.messageCells {
+		if cell[0] == x && cell[1] == y {
+			return true
+		}
+	}
+	return false
+}
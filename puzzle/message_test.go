@@ -0,0 +1,72 @@
+package puzzle
+
+import (
+	"testing"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	if message := normalizeMessage("You did it!"); message != "YOUDIDIT" {
+		t.Errorf("Expected normalizeMessage to return %q, got %q", "YOUDIDIT", message)
+	}
+}
+
+func TestPlaceHiddenMessage(t *testing.T) {
+	puzzle := createPuzzle(4, 3)
+	placeWord(&puzzle, "DOG", 1, 0, 1, 0, true)
+
+	if err := placeHiddenMessage(&puzzle, "HELLO"); err != nil {
+		t.Fatalf("placeHiddenMessage returned error: %v", err)
+	}
+
+	// Reading the cells that are not part of a search word in order spells the message,
+	// and the cells after it are blanked
+	if leftover := leftoverLetters(puzzle); leftover != "HELLO" {
+		t.Errorf("Expected the leftover letters to spell %q, got %q", "HELLO", leftover)
+	}
+	if !puzzle.isMessageCell(0, 0) || puzzle.isMessageCell(1, 0) {
+		t.Errorf("Message cells were not recorded correctly: %v", puzzle.messageCells)
+	}
+	if !isMaskedCell(puzzle.grid, 3, 2) || puzzle.solution[3][2] != maskedCell {
+		t.Errorf("Expected the cells after the message to be blanked")
+	}
+	if puzzle.HiddenMessage() != "HELLO" {
+		t.Errorf("Expected hidden message %q, got %q", "HELLO", puzzle.HiddenMessage())
+	}
+}
+
+func TestPlaceHiddenMessageTooLong(t *testing.T) {
+	puzzle := createPuzzle(2, 2)
+	placeWord(&puzzle, "AB", 0, 0, 1, 0, true)
+
+	if err := placeHiddenMessage(&puzzle, "HELLO"); err == nil {
+		t.Errorf("Expected an error when the message does not fit")
+	}
+}
+
+func TestGeneratePuzzleWithHiddenMessage(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Size: 6, Difficulty: 9, Seed: 5, HiddenMessage: "Well done",
+		Words: WordEntries([]string{"CAT", "DOG", "BIRD", "FISH", "LION"})}
+
+	puzzle, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+
+	if leftover := leftoverLetters(puzzle); leftover != "WELLDONE" {
+		t.Errorf("Expected the leftover letters to spell %q, got %q", "WELLDONE", leftover)
+	}
+}
+
+// leftoverLetters reads the letters that are not part of a search word in order.
+func leftoverLetters(puzzle Puzzle) string {
+	leftover := make([]rune, 0)
+	for y := 0; y < puzzle.Height(); y++ {
+		for x := 0; x < puzzle.Width(); x++ {
+			if puzzle.solution[x][y] == ' ' {
+				leftover = append(leftover, puzzle.grid[x][y])
+			}
+		}
+	}
+	return string(leftover)
+}
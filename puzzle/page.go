@@ -0,0 +1,114 @@
+package puzzle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultMargin = 20
+
+// Page sizes in mm, portrait.
+var pageSizes = map[string][2]float64{
+	"a3":      {297, 420},
+	"a4":      {210, 297},
+	"a5":      {148, 210},
+	"letter":  {215.9, 279.4},
+	"legal":   {215.9, 355.6},
+	"tabloid": {279.4, 431.8},
+}
+
+// PageSetup describes the paper a puzzle is printed on.  Zero values mean A4,
+// portrait, with 20mm margins.
+type PageSetup struct {
+	Size        string   `yaml:"page_size"`
+	Orientation string   `yaml:"orientation"`
+	Margins     *Margins `yaml:"margins"`
+}
+
+// Margins are the page margins in mm.  In YAML they are either a single number used
+// for all four sides, or a mapping with any of top, bottom, left and right; sides
+// that are left out keep the 20mm default.
+type Margins struct {
+	Top    float64 `yaml:"top"`
+	Bottom float64 `yaml:"bottom"`
+	Left   float64 `yaml:"left"`
+	Right  float64 `yaml:"right"`
+}
+
+func (m *Margins) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if tag := value.ShortTag(); tag != "!!int" && tag != "!!float" {
+			return &ConfigError{Line: value.Line, Column: value.Column,
+				Message: fmt.Sprintf("margins must be a number or a mapping, got %q", value.Value)}
+		}
+		var margin float64
+		if err := value.Decode(&margin); err != nil {
+			return err
+		}
+		*m = Margins{Top: margin, Bottom: margin, Left: margin, Right: margin}
+		return nil
+	}
+
+	// Decode into a plain struct so this method is not called again
+	type sides Margins
+	margins := sides{Top: defaultMargin, Bottom: defaultMargin, Left: defaultMargin, Right: defaultMargin}
+	if err := value.Decode(&margins); err != nil {
+		return err
+	}
+	*m = Margins(margins)
+	return nil
+}
+
+// PageSize returns the width and height of the page in mm, taking the orientation
+// into account.
+func (p PageSetup) PageSize() (float64, float64, error) {
+	name := strings.ToLower(p.Size)
+	if name == "" {
+		name = "a4"
+	}
+	size, ok := pageSizes[name]
+	if !ok {
+		names := make([]string, 0, len(pageSizes))
+		for name := range pageSizes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return 0, 0, fmt.Errorf("unknown page size %q (expected one of %s)", p.Size, strings.Join(names, ", "))
+	}
+
+	switch strings.ToLower(p.Orientation) {
+	case "", "portrait":
+		return size[0], size[1], nil
+	case "landscape":
+		return size[1], size[0], nil
+	}
+	return 0, 0, fmt.Errorf("unknown orientation %q (expected portrait or landscape)", p.Orientation)
+}
+
+// margins returns the configured margins, or the defaults.
+func (p PageSetup) margins() Margins {
+	if p.Margins == nil {
+		return Margins{Top: defaultMargin, Bottom: defaultMargin, Left: defaultMargin, Right: defaultMargin}
+	}
+	return *p.Margins
+}
+
+// validate checks that the page size and orientation are known and that the margins
+// leave room for the puzzle.
+func (p PageSetup) validate() error {
+	width, height, err := p.PageSize()
+	if err != nil {
+		return err
+	}
+	m := p.margins()
+	if m.Top < 0 || m.Bottom < 0 || m.Left < 0 || m.Right < 0 {
+		return fmt.Errorf("margins must not be negative")
+	}
+	if m.Left+m.Right >= width/2 || m.Top+m.Bottom >= height/2 {
+		return fmt.Errorf("margins leave less than half the page for the puzzle")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package puzzle
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestPageSetupYAML(t *testing.T) {
+	tests := []struct {
+		yaml     string
+		expected Margins
+	}{
+		{"margins: 10", Margins{Top: 10, Bottom: 10, Left: 10, Right: 10}},
+		{"margins: {top: 5, left: 12.5}", Margins{Top: 5, Bottom: 20, Left: 12.5, Right: 20}},
+	}
+	for _, test := range tests {
+		var page PageSetup
+		if err := yaml.Unmarshal([]byte(test.yaml), &page); err != nil {
+			t.Fatalf("Unmarshal(%q) returned error: %v", test.yaml, err)
+		}
+		if page.margins() != test.expected {
+			t.Errorf("Unmarshal(%q): expected margins %+v, got %+v", test.yaml, test.expected, page.margins())
+		}
+	}
+
+	var page PageSetup
+	if err := yaml.Unmarshal([]byte("margins: wide"), &page); err == nil {
+		t.Errorf("Expected an error for margins that are not a number")
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		page          PageSetup
+		width, height float64
+	}{
+		{PageSetup{}, 210, 297},
+		{PageSetup{Size: "Letter"}, 215.9, 279.4},
+		{PageSetup{Size: "a4", Orientation: "landscape"}, 297, 210},
+	}
+	for _, test := range tests {
+		width, height, err := test.page.PageSize()
+		if err != nil {
+			t.Fatalf("PageSize(%+v) returned error: %v", test.page, err)
+		}
+		if width != test.width || height != test.height {
+			t.Errorf("PageSize(%+v) = %gx%g, expected %gx%g", test.page, width, height, test.width, test.height)
+		}
+	}
+
+	for _, page := range []PageSetup{{Size: "B5"}, {Orientation: "sideways"}} {
+		if _, _, err := page.PageSize(); err == nil {
+			t.Errorf("Expected an error for %+v", page)
+		}
+	}
+}
+
+func TestPageSetupValidate(t *testing.T) {
+	if err := (PageSetup{Margins: &Margins{Left: 60, Right: 60}}).validate(); err == nil {
+		t.Errorf("Expected an error for margins wider than the page allows")
+	}
+	if err := (PageSetup{Margins: &Margins{Top: -1}}).validate(); err == nil {
+		t.Errorf("Expected an error for a negative margin")
+	}
+	if err := (PageSetup{Size: "letter", Margins: &Margins{}}).validate(); err != nil {
+		t.Errorf("Expected zero margins to be valid, got %v", err)
+	}
+}
+
+func TestPageLayoutFitsHeight(t *testing.T) {
+	// A tall grid on a landscape page is limited by the height and centered
+	puzzle := createPuzzle(10, 30)
+	page := PageSetup{Orientation: "landscape"}
+	layout, err := NewPageLayout(page, puzzle, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPageLayout returned error: %v", err)
+	}
+	if layout.GridBottom(puzzle) > layout.SeedY()-wordListGap-layout.WordCellHeight+0.001 {
+		t.Errorf("Expected the grid to leave room for the word list, bottom at %g", layout.GridBottom(puzzle))
+	}
+	gridWidth := layout.CellSize * float64(puzzle.Width())
+	left, right := layout.GridX-layout.LeftMargin, layout.PageWidth-layout.RightMargin-(layout.GridX+gridWidth)
+	if left < 1 || left-right > 0.001 || right-left > 0.001 {
+		t.Errorf("Expected the grid to be centered, got %g mm left and %g mm right", left, right)
+	}
+
+	// A wide grid fills the width between the margins
+	puzzle = createPuzzle(30, 10)
+	layout, err = NewPageLayout(page, puzzle, 0, 0)
+	if err != nil {
+		t.Fatalf("NewPageLayout returned error: %v", err)
+	}
+	if layout.GridX != layout.LeftMargin || layout.CellSize != layout.ContentWidth()/30 {
+		t.Errorf("Expected the grid to fill the page width")
+	}
+}
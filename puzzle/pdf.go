@@ -2,128 +2,192 @@ package puzzle
 
 import (
 	"fmt"
+	"math"
+	"os"
 
 	"github.com/jung-kurt/gofpdf"
 )
 
-const leftMargin = 20
-const rightMargin = 20
-const topMargin = 20
-const bottomMargin = 20
+func init() {
+	RegisterRenderer("pdf", newPDFRenderer)
+}
 
 func GeneratePDF(puzzle Puzzle, title string, words []string, columns int, outputFile string,
-	backgroundFile string) (Puzzle, error) {
-	// Create a new PDF instance
+	options RenderOptions, page PageSetup) (Puzzle, error) {
+	r := newPDFRenderer(options)
+	err := RenderPuzzle(r, puzzle, title, words, columns, page, outputFile)
+	if err != nil {
+		return puzzle, err
+	}
+
+	return puzzle, nil
+}
+
+// Font families registered for the custom TrueType fonts.
+const (
+	pdfTextFont = "text"
+	pdfGridFont = "grid"
+)
+
+// pdfRenderer draws puzzle pages into a PDF document.
+type pdfRenderer struct {
+	pdf        *gofpdf.Fpdf
+	layout     PageLayout
+	background string
+
+	// Font families for the title and word list, and for the grid letters
+	textFont string
+	gridFont string
+	// Converts text for the core fonts, which only cover Latin-1
+	translate func(string) string
+}
+
+func newPDFRenderer(options RenderOptions) Renderer {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	// Everything is placed by the page layout, so text near the bottom margin must not
+	// start a new page.
+	pdf.SetAutoPageBreak(false, 0)
+	r := &pdfRenderer{pdf: pdf, background: options.Background, textFont: "Arial", gridFont: "Courier",
+		translate: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	// TrueType fonts are embedded as UTF-8 fonts, so any script they cover prints
+	// correctly.  The grid uses the text font unless it has its own.
+	if options.Font != "" {
+		addUTF8Font(pdf, pdfTextFont, options.Font)
+		r.textFont, r.gridFont = pdfTextFont, pdfTextFont
+		r.translate = func(s string) string { return s }
+	}
+	if options.GridFont != "" {
+		addUTF8Font(pdf, pdfGridFont, options.GridFont)
+		r.gridFont = pdfGridFont
+	}
+	return r
+}
 
-	// Add a new page
-	pdf.AddPage()
+// addUTF8Font registers a TrueType font file for both the regular and bold styles.
+// A font that cannot be read leaves the error on pdf.
+func addUTF8Font(pdf *gofpdf.Fpdf, family, fontFile string) {
+	data, err := os.ReadFile(fontFile)
+	if err != nil {
+		pdf.SetError(fmt.Errorf("failed to load font: %v", err))
+		return
+	}
+	pdf.AddUTF8FontFromBytes(family, "", data)
+	pdf.AddUTF8FontFromBytes(family, "B", data)
+}
 
-	// Draw tiled background
-	if backgroundFile != "" {
-		drawBackground(pdf, backgroundFile)
-		// Draw a rectangle in the center of the page for the puzzle and words
-		pageW, pageH := pdf.GetPageSize()
-		pdf.SetFillColor(255, 255, 255) // Set fill color to white
-		pdf.Rect(leftMargin, topMargin, pageW-(leftMargin+rightMargin), pageH-(topMargin+bottomMargin), "F")
+// gridText converts a grid letter for the grid font.
+func (r *pdfRenderer) gridText(s string) string {
+	if r.gridFont == "Courier" {
+		return r.translate(s)
 	}
+	return s
+}
 
-	// Set margins (in mm)
-	pdf.SetMargins(leftMargin, topMargin, rightMargin)
+func (r *pdfRenderer) NewPage(layout PageLayout) error {
+	r.layout = layout
+	startPDFPage(r.pdf, layout, r.background)
+	return r.pdf.Error()
+}
 
-	// Set font, size, and styles
-	pdf.SetFont("Arial", "", 14)
+func (r *pdfRenderer) DrawTitle(title string) {
+	r.drawTitle(title, r.layout)
+}
 
-	// Add the title
-	drawTitle(pdf, title, topMargin)
-	pdf.Ln(10)
+func (r *pdfRenderer) DrawGrid(puzzle Puzzle) {
+	r.drawPuzzleGrid(puzzle, r.layout, false)
+	r.drawSeed(puzzle.seed, r.layout)
+}
 
-	// Draw the puzzle grid
-	drawPuzzleGrid(pdf, puzzle, false)
+func (r *pdfRenderer) DrawWordList(words []string) {
+	r.listSearchWords(words, r.layout, false)
+}
 
-	// Draw search words
-	listSearchWords(pdf, words, columns)
+func (r *pdfRenderer) DrawClueList(clues []Clue, withAnswers bool) {
+	r.listSearchWords(clueText(clues, withAnswers), r.layout, true)
+}
+
+func (r *pdfRenderer) DrawSolution(puzzle Puzzle) {
+	r.drawPuzzleGrid(puzzle, r.layout, true)
 
-	// Add solution page
-	pdf.AddPage()
+	// Reveal the hidden message
+	if puzzle.hiddenMessage != "" {
+		r.drawHiddenMessage(puzzle.hiddenMessage, r.layout, r.layout.MessageY())
+	}
+
+	r.drawSeed(puzzle.seed, r.layout)
+}
+
+func (r *pdfRenderer) Save(outputFile string) error {
+	return r.pdf.OutputFileAndClose(outputFile)
+}
+
+// startPDFPage adds a new page of the layout's size with the tiled background, if any.
+func startPDFPage(pdf *gofpdf.Fpdf, layout PageLayout, backgroundFile string) {
+	pdf.AddPageFormat("P", gofpdf.SizeType{Wd: layout.PageWidth, Ht: layout.PageHeight})
 
 	// Draw tiled background
 	if backgroundFile != "" {
 		drawBackground(pdf, backgroundFile)
 		// Draw a rectangle in the center of the page for the puzzle and words
-		pageW, pageH := pdf.GetPageSize()
 		pdf.SetFillColor(255, 255, 255) // Set fill color to white
-		pdf.Rect(leftMargin, topMargin, pageW-(leftMargin+rightMargin), pageH-(topMargin+bottomMargin), "F")
+		pdf.Rect(layout.LeftMargin, layout.TopMargin, layout.ContentWidth(), layout.ContentHeight(), "F")
 	}
 
 	// Set margins (in mm)
-	pdf.SetMargins(leftMargin, topMargin, rightMargin)
-
-	// Set font, size, and styles
-	pdf.SetFont("Arial", "", 14)
-
-	// Add the title
-	drawTitle(pdf, title, topMargin)
-	pdf.Ln(10)
-
-	// Draw the puzzle grid
-	drawPuzzleGrid(pdf, puzzle, true)
+	pdf.SetMargins(layout.LeftMargin, layout.TopMargin, layout.RightMargin)
+}
 
-	// Save the PDF to a file
-	err := pdf.OutputFileAndClose(outputFile)
-	if err != nil {
-		return puzzle, err
-	}
+func (r *pdfRenderer) drawTitle(title string, layout PageLayout) {
+	r.pdf.SetFont(r.textFont, "B", titleFontSize)
+	title = r.translate(title)
+	titleWidth := r.pdf.GetStringWidth(title)
+	titleX := (layout.PageWidth - titleWidth) / 2
 
-	return puzzle, nil
+	r.pdf.SetXY(titleX, layout.TitleY)
+	r.pdf.CellFormat(titleWidth, layout.TitleHeight, title, "", 0, "C", false, 0, "")
 }
 
-func drawTitle(pdf *gofpdf.Fpdf, title string, marginY float64) {
-	pdf.SetFont("Arial", "B", 24)
-	titleWidth := pdf.GetStringWidth(title)
-	pageWidth, _ := pdf.GetPageSize()
-	titleX := (pageWidth - titleWidth) / 2
+// drawHiddenMessage prints the hidden message across the page at the given y position.
+func (r *pdfRenderer) drawHiddenMessage(message string, layout PageLayout, y float64) {
+	r.pdf.SetFont(r.textFont, "B", messageFontSize)
+	r.pdf.SetXY(layout.LeftMargin, y)
+	r.pdf.CellFormat(layout.ContentWidth(), 10, r.translate("Hidden message: "+message), "", 1, "C", false, 0, "")
+}
 
-	pdf.SetXY(titleX, marginY)
-	pdf.CellFormat(titleWidth, 10, title, "", 0, "C", false, 0, "")
+// drawSeed prints the puzzle seed in small grey text at the bottom of the page so
+// a printed puzzle can be regenerated later.
+func (r *pdfRenderer) drawSeed(seed int64, layout PageLayout) {
+	r.pdf.SetFont(r.textFont, "", seedFontSize)
+	r.pdf.SetTextColor(128, 128, 128)
+	r.pdf.SetXY(layout.LeftMargin, layout.SeedY())
+	r.pdf.CellFormat(layout.ContentWidth(), 5, fmt.Sprintf("Seed: %d", seed), "", 0, "R", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
 }
 
 // Draw a grid for the given puzzle, with the optimal font and cell size, and add
 // a line for each placed word in the solution grid if applicable.
-func drawPuzzleGrid(pdf *gofpdf.Fpdf, puzzle Puzzle, isSolution bool) {
-	// Calculate the optimal font size and cell size based on the grid size and available page width
-	gridSize := len(puzzle.grid)
-	pageWidth, _ := pdf.GetPageSize()
-	leftMargin, _, rightMargin, _ := pdf.GetMargins()
-
-	maxCellWidth := (pageWidth - (leftMargin + rightMargin)) / float64(gridSize)
-	//fontSize := maxCellWidth * 0.8
-	fontSize := maxCellWidth * 2.0
-	cellSize := maxCellWidth
-
-	pdf.SetFont("Courier", "B", fontSize)
+func (r *pdfRenderer) drawPuzzleGrid(puzzle Puzzle, layout PageLayout, isSolution bool) {
+	pdf := r.pdf
+	cellSize := layout.CellSize
+	pdf.SetFont(r.gridFont, "B", layout.GridFontSize())
 
 	var gr [][]rune
 	if isSolution {
 		gr = puzzle.solution
-		pageX, pageY := pdf.GetXY()
-		pdf.SetLineWidth(1)             // Set the line width
-		pdf.SetDrawColor(192, 192, 192) // Set the line color (black)
-		for _, word := range puzzle.placedWords {
-			startX := float64(word.x)*cellSize + (cellSize / 2)
-			startY := float64(word.y)*cellSize + (cellSize / 2)
-			endX := startX + float64(word.dx*(len(word.word)-1))*cellSize
-			endY := startY + float64(word.dy*(len(word.word)-1))*cellSize
-			pdf.Line(pageX+startX, pageY+startY, pageX+endX, pageY+endY)
+		pdf.SetLineWidth(math.Min(1, cellSize/4)) // Set the line width, thinner for small grids
+		pdf.SetDrawColor(192, 192, 192)           // Set the line color (light grey)
+		for _, location := range puzzle.Locations() {
+			pdf.Line(layout.WordEnds(location))
 		}
 	} else {
 		gr = puzzle.grid
 	}
-	for y := 0; y < len(gr); y++ {
-		for x := 0; x < len(puzzle.grid); x++ {
-			pdf.CellFormat(cellSize, cellSize, string(gr[x][y]), "0", 0, "C", false, 0, "")
+	for y := 0; y < puzzle.Height(); y++ {
+		pdf.SetXY(layout.CellPosition(0, y))
+		for x := 0; x < puzzle.Width(); x++ {
+			pdf.CellFormat(cellSize, cellSize, r.gridText(cellText(gr[x][y])), "0", 0, "C", false, 0, "")
 		}
-		pdf.Ln(-1)
 	}
 }
 
@@ -164,28 +228,21 @@ func drawBackground(pdf *gofpdf.Fpdf, imagePath string) error {
 	return nil
 }
 
-func listSearchWords(pdf *gofpdf.Fpdf, words []string, columns int) {
-	fontSize := float64(11)
-	pdf.SetFont("Arial", "", fontSize)
-	pdf.Ln(10)
-	pageWidth, _ := pdf.GetPageSize()
-	leftMargin, _, rightMargin, _ := pdf.GetMargins()
-	effectiveWidth := pageWidth - leftMargin - rightMargin
+// listSearchWords prints the word list.  When numbered is true each entry is numbered
+// and left aligned, as suits a list of clues.
+func (r *pdfRenderer) listSearchWords(words []string, layout PageLayout, numbered bool) {
+	r.pdf.SetFont(r.textFont, "", layout.WordFontSize)
 
-	if columns < 1 {
-		columns = 5
+	align := "C"
+	if numbered {
+		align = "L"
 	}
-	wordCount := 0
-	width := effectiveWidth / float64(columns)
-
-	for _, word := range words {
-		//pdf.CellFormat(40, 10, word, "0", 0, "L", false, 0, "")
-		pdf.CellFormat(width, 10, word, "0", 0, "C", false, 0, "")
-		wordCount++
-
-		if wordCount%columns == 0 {
-			//pdf.Ln(-1)
-			pdf.Ln(fontSize / 2)
+	for i, word := range words {
+		if numbered {
+			word = fmt.Sprintf("%d. %s", i+1, word)
 		}
+		x, y := layout.WordPosition(i)
+		r.pdf.SetXY(x, y)
+		r.pdf.CellFormat(layout.WordColumnWidth, layout.WordCellHeight, r.translate(word), "0", 0, align, false, 0, "")
 	}
 }
@@ -0,0 +1,53 @@
+package puzzle
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testFont finds a TrueType font shipped with gofpdf in the module cache.
+func testFont(t *testing.T) string {
+	t.Helper()
+	modCache := os.Getenv("GOMODCACHE")
+	if modCache == "" {
+		modCache = filepath.Join(build.Default.GOPATH, "pkg", "mod")
+	}
+	fonts, _ := filepath.Glob(filepath.Join(modCache, "github.com", "jung-kurt", "gofpdf@*", "font",
+		"DejaVuSansCondensed.ttf"))
+	if len(fonts) == 0 {
+		t.Skip("DejaVuSansCondensed.ttf not found in the module cache")
+	}
+	return fonts[0]
+}
+
+func TestGeneratePDFWithFont(t *testing.T) {
+	puzzle := createPuzzle(4, 4)
+	placeWord(&puzzle, "ΑΛΦΑ", 0, 0, 1, 0, true)
+	outputFile := filepath.Join(t.TempDir(), "greek.pdf")
+	options := RenderOptions{Font: testFont(t)}
+	if _, err := GeneratePDF(puzzle, "Ελληνικά", []string{"ΑΛΦΑ"}, 0, outputFile, options, PageSetup{}); err != nil {
+		t.Fatalf("GeneratePDF returned error: %v", err)
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read PDF output: %v", err)
+	}
+	if !strings.Contains(string(data), "/FontFile2") {
+		t.Errorf("Expected the TrueType font to be embedded in the PDF")
+	}
+}
+
+func TestGeneratePDFMissingFont(t *testing.T) {
+	puzzle := createPuzzle(4, 4)
+	outputDir := t.TempDir()
+	for _, options := range []RenderOptions{{Font: filepath.Join(outputDir, "missing.ttf")},
+		{GridFont: filepath.Join(outputDir, "missing.ttf")}} {
+		_, err := GeneratePDF(puzzle, "Title", nil, 0, filepath.Join(outputDir, "puzzle.pdf"), options, PageSetup{})
+		if err == nil || !strings.Contains(err.Error(), "missing.ttf") {
+			t.Errorf("Expected an error naming the missing font, got %v", err)
+		}
+	}
+}
@@ -5,19 +5,36 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
-	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 )
 
+// defaultMaxAttempts is the number of word placements the backtracking search tries
+// before giving up when the config does not specify max_attempts.
+const defaultMaxAttempts = 10000
+
+// maxRepairRounds limits how many times ensureUniqueWords re-rolls letters before
+// giving up.
+const maxRepairRounds = 1000
+
 var logger *logrus.Logger
 
 func init() {
-	rand.Seed(time.Now().UnixNano())
 	logger = logrus.New()
 }
 
+// FitError reports that the words or the hidden message do not fit in the grid, a
+// failure that a larger grid may fix.
+type FitError struct {
+	Message string
+}
+
+func (e *FitError) Error() string {
+	return e.Message
+}
+
 type placedSearchWord struct {
 	word string
 	x    int
@@ -29,31 +46,103 @@ type Puzzle struct {
 	grid        [][]rune
 	solution    [][]rune
 	placedWords []placedSearchWord
+	title       string
+	seed        int64
+
+	hiddenMessage string
+	messageCells  [][2]int
+
+	// The search words in the order they were given, with the form shown in the word
+	// list and the form placed in the grid, and the index in placedWords of each (-1
+	// for a word that was not placed)
+	words      []WordEntry
+	placements []int
+	// Clues shown in place of the word list, if any
+	clues []Clue
+
+	// Letters used to fill the grid, and how they are picked
+	alphabet []rune
+	filler   Filler
 }
 
 type Grid [][]rune
 
-// GeneratePuzzle creates a Word Search puzzle based on the provided gridSize, words, columns, and difficulty.
+// Seed returns the random seed the puzzle was generated from.  Generating a puzzle
+// from the same config with this seed reproduces it exactly.
+func (p Puzzle) Seed() int64 {
+	return p.seed
+}
+
+// GeneratePuzzle creates a Word Search puzzle based on the size, words and difficulty in
+// the provided config.  If config.Seed is zero a seed is picked from the current time.
 // The dictionaryPath is used to load the dictionary for generating random letters in the grid.
-func GeneratePuzzle(gridSize int, words []string, columns int, difficulty int, dictionaryPath string,
+func GeneratePuzzle(config *PuzzleConfig, dictionaryPath string, verbose bool) (Puzzle, error) {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return generatePuzzle(config, dictionaryPath, rand.New(rand.NewSource(seed)), seed, verbose)
+}
+
+// generatePuzzle does the work of GeneratePuzzle, drawing every random choice from rng.
+func generatePuzzle(config *PuzzleConfig, dictionaryPath string, rng *rand.Rand, seed int64,
 	verbose bool) (Puzzle, error) {
+	width, height := config.Dimensions()
+	difficulty := config.Difficulty
+	maxAttempts := config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
 	// Set the default log level
 	if verbose {
 		logger.SetLevel(logrus.DebugLevel)
 	} else {
 		logger.SetLevel(logrus.InfoLevel)
 	}
-	logger.Logf(logrus.InfoLevel, "Generating puzzle...")
+	logger.Logf(logrus.InfoLevel, "Generating puzzle (seed %d)...", seed)
+
+	puzzle := createPuzzle(width, height)
+	puzzle.title = config.Title
+	puzzle.seed = seed
 
-	puzzle := createPuzzle(gridSize)
+	if config.Shape != "" {
+		shape, err := LoadShape(config.Shape, width, height)
+		if err != nil {
+			return puzzle, err
+		}
+		applyShape(&puzzle, shape)
+	}
 
 	// Validate difficulty
 	if difficulty < 1 || difficulty > 9 {
 		return puzzle, fmt.Errorf("invalid difficulty %d (only 1-9 allowed)", difficulty)
 	}
 
+	directions, err := puzzleDirections(config.Directions, difficulty)
+	if err != nil {
+		return puzzle, err
+	}
+
 	// Validate & process words
-	validWords, err := processWords(words, gridSize)
+	// The longest word that can fit runs along the longer side of the grid
+	gridSize := width
+	if height > gridSize {
+		gridSize = height
+	}
+	gridWords, err := config.GridWords()
+	if err != nil {
+		return puzzle, err
+	}
+	puzzle.words = make([]WordEntry, 0, len(config.Words))
+	for i, entry := range config.Words {
+		puzzle.words = append(puzzle.words, WordEntry{Display: entry.DisplayText(), Grid: gridWords[i]})
+	}
+	puzzle.clues, err = wordClues(config.Words)
+	if err != nil {
+		return puzzle, err
+	}
+	validWords, wordIndexes, err := processWords(gridWords, gridSize)
 	if err != nil {
 		return puzzle, err
 	}
@@ -61,21 +150,56 @@ func GeneratePuzzle(gridSize int, words []string, columns int, difficulty int, d
 		if !isValidWord(word) {
 			return puzzle, fmt.Errorf("invalid word '%s'", word)
 		}
-		if len(word) > gridSize {
-			return puzzle, fmt.Errorf("invalid word '%s' (%d is too long)", word, len(word))
+		if length := utf8.RuneCountInString(word); length > gridSize {
+			return puzzle, &FitError{Message: fmt.Sprintf("invalid word '%s' (%d is too long)", word, length)}
 		}
 	}
 
-	err = insertWordsIntoGrid(&puzzle, validWords, difficulty, dictionaryPath, verbose)
+	dictionary, err := LoadDictionary(dictionaryPath)
+	if err != nil {
+		return puzzle, err
+	}
+	puzzle.alphabet, err = puzzleAlphabet(config.Alphabet, config.Language, validWords)
+	if err != nil {
+		return puzzle, err
+	}
+	character, err := fillCharacter(config.FillCharacter)
+	if err != nil {
+		return puzzle, err
+	}
+	puzzle.filler, err = NewFiller(config.Fill, FillerOptions{Alphabet: puzzle.alphabet, Words: validWords,
+		Dictionary: dictionary, Character: character})
+	if err != nil {
+		return puzzle, err
+	}
+
+	err = insertWordsIntoGrid(&puzzle, validWords, difficulty, dictionary, directions, maxAttempts,
+		normalizeMessage(config.HiddenMessage), rng, verbose)
 	if err != nil {
 		return puzzle, err
 	}
 
-	err = fillEmptyCells(puzzle.grid)
+	// The search words are placed in the order they were passed in
+	puzzle.placements = make([]int, len(puzzle.words))
+	for i := range puzzle.placements {
+		puzzle.placements[i] = -1
+	}
+	for i, index := range wordIndexes {
+		puzzle.placements[index] = i
+	}
+
+	err = fillEmptyCells(puzzle.grid, puzzle.filler, rng)
 	if err != nil {
 		return puzzle, err
 	}
 
+	if !config.AllowDuplicates {
+		err = ensureUniqueWords(&puzzle, rng)
+		if err != nil {
+			return puzzle, err
+		}
+	}
+
 	for _, placedWord := range puzzle.placedWords {
 		logger.Logf(logrus.DebugLevel, "Word: %s, X: %d, Y: %d, dX: %d, dY: %d\n",
 			placedWord.word, placedWord.x, placedWord.y, placedWord.dx, placedWord.dy)
@@ -84,79 +208,143 @@ func GeneratePuzzle(gridSize int, words []string, columns int, difficulty int, d
 	return puzzle, nil
 }
 
-func createPuzzle(size int) Puzzle {
+// gridWord returns the grid form of a word from the word list.  Words the puzzle was
+// not generated with are only normalized.
+func (p Puzzle) gridWord(display string) string {
+	for _, entry := range p.words {
+		if entry.Display == display {
+			return entry.Grid
+		}
+	}
+	return normalizeWord(display)
+}
+
+// Entries returns the search words in the order they were given, each with the form
+// shown in the word list and the form placed in the grid.  A puzzle that does not
+// know how its words were written, such as one saved as JSON by an older version,
+// returns its placed words.
+func (p Puzzle) Entries() []WordEntry {
+	if len(p.words) == 0 {
+		return WordEntries(p.Words())
+	}
+	return p.words
+}
+
+// Width returns the number of columns in the puzzle grid.
+func (p Puzzle) Width() int {
+	return len(p.grid)
+}
+
+// Height returns the number of rows in the puzzle grid.
+func (p Puzzle) Height() int {
+	if len(p.grid) == 0 {
+		return 0
+	}
+	return len(p.grid[0])
+}
+
+func createPuzzle(width, height int) Puzzle {
 	var puzzle Puzzle
 
-	puzzle.grid = createEmptyGrid(size)
-	puzzle.solution = createEmptyGrid(size)
+	puzzle.grid = createEmptyGrid(width, height)
+	puzzle.solution = createEmptyGrid(width, height)
+	puzzle.alphabet = []rune(latinLetters)
+	puzzle.filler = uniformWeights(puzzle.alphabet)
 
 	return puzzle
 
 }
 
-// createEmptyGrid initializes an empty square grid with the specified size.
-func createEmptyGrid(size int) Grid {
-	grid := make(Grid, size)
-	for i := 0; i < size; i++ {
-		grid[i] = make([]rune, size)
-		for j := 0; j < size; j++ {
+// createEmptyGrid initializes an empty grid with the specified width and height.
+// The grid is indexed as grid[x][y].
+func createEmptyGrid(width, height int) Grid {
+	grid := make(Grid, width)
+	for i := 0; i < width; i++ {
+		grid[i] = make([]rune, height)
+		for j := 0; j < height; j++ {
 			grid[i][j] = ' '
 		}
 	}
 	return grid
 }
 
-func processWords(words []string, gridSize int) ([]string, error) {
+// processWords normalizes the words, leaving out those with no letters, and sorts them
+// with the longest first.  It also returns the index in words of each word it returns.
+func processWords(words []string, gridSize int) ([]string, []int, error) {
 	validWords := make([]string, 0)
+	indexes := make([]int, 0)
 
-	for _, word := range words {
-		word = strings.ToUpper(strings.ReplaceAll(word, " ", ""))
+	for i, word := range words {
+		word = normalizeWord(word)
 		if len(word) == 0 {
 			continue
 		}
 
-		if len(word) > gridSize {
-			return nil, fmt.Errorf("word %s is too long for the grid size", word)
+		if utf8.RuneCountInString(word) > gridSize {
+			return nil, nil, &FitError{Message: fmt.Sprintf("word %s is too long for the grid size", word)}
 		}
 
 		validWords = append(validWords, word)
+		indexes = append(indexes, i)
 	}
 
 	// Sort by word length so longest is first.  It's easier to place long words in
 	// the puzzle early.
-	sort.Slice(validWords, func(i, j int) bool {
-		return len(validWords[i]) > len(validWords[j])
-	})
+	sort.Sort(byLength{validWords, indexes})
 
 	if len(validWords) == 0 {
-		return nil, errors.New("no valid words provided")
+		return nil, nil, errors.New("no valid words provided")
 	}
 
-	return validWords, nil
+	return validWords, indexes, nil
 }
 
-func insertWordsIntoGrid(puzzle *Puzzle, words []string, difficulty int, dictionaryPath string, verbose bool) error {
-	dictionary, err := LoadDictionary(dictionaryPath)
+// byLength sorts words with the longest first, keeping the index of each word with it.
+type byLength struct {
+	words   []string
+	indexes []int
+}
+
+func (b byLength) Len() int { return len(b.words) }
+
+func (b byLength) Less(i, j int) bool {
+	return utf8.RuneCountInString(b.words[i]) > utf8.RuneCountInString(b.words[j])
+}
+
+func (b byLength) Swap(i, j int) {
+	b.words[i], b.words[j] = b.words[j], b.words[i]
+	b.indexes[i], b.indexes[j] = b.indexes[j], b.indexes[i]
+}
+
+func insertWordsIntoGrid(puzzle *Puzzle, words []string, difficulty int, dictionary *Dictionary,
+	directions [][2]int, maxAttempts int, hiddenMessage string, rng *rand.Rand, verbose bool) error {
+	randomWords := dictionary.RandomWords(rng, 10*numberOfRandomWords(difficulty))
+
+	logger.Logf(logrus.DebugLevel, "Inserting search words.\n")
+	err := placeSearchWords(puzzle, words, directions, maxAttempts, rng, verbose)
 	if err != nil {
 		return err
 	}
 
-	randomWords := dictionary.RandomWords(10 * numberOfRandomWords(difficulty))
+	// The leftover letters spell the hidden message, so there is no room for decoys
+	if hiddenMessage != "" {
+		logger.Logf(logrus.DebugLevel, "Inserting hidden message.\n")
+		return placeHiddenMessage(puzzle, hiddenMessage)
+	}
 
-	logger.Logf(logrus.DebugLevel, "Inserting search words.\n")
-	for _, word := range words {
-		logger.Logf(logrus.DebugLevel, "Attempting to insert word: %s\n", word)
-		if !tryInsertWord(puzzle, word, true, verbose) {
-			return errors.New("Failed to insert word into the grid: " + word)
-		}
-		logger.Logf(logrus.DebugLevel, "Successfully inserted word: %s\n", word)
+	// A fixed filler leaves the grid with nothing but the search words
+	if _, fixed := puzzle.filler.(fixedFiller); fixed {
+		return nil
 	}
 
 	logger.Logf(logrus.DebugLevel, "Inserting random words.\n")
 	numRandom := 0
 	for _, word := range randomWords {
+		if !inAlphabet(word, puzzle.alphabet) {
+			continue
+		}
 		logger.Logf(logrus.DebugLevel, "Attempting to insert random word: %s\n", word)
-		if !tryInsertWord(puzzle, word, false, verbose) {
+		if !tryInsertWord(puzzle, word, false, directions, rng, verbose) {
 			logger.Logf(logrus.DebugLevel, "Failed to insert random word: %s\n", word)
 		} else {
 			logger.Logf(logrus.DebugLevel, "Inserted random word: %s\n", word)
@@ -166,40 +354,110 @@ func insertWordsIntoGrid(puzzle *Puzzle, words []string, difficulty int, diction
 	logger.Logf(logrus.InfoLevel, "Successfully inserted %d random words\n", numRandom)
 
 	logger.Logf(logrus.DebugLevel, "Inserting close words.\n")
-	adjustedWords := adjustWordsForDifficulty(words, difficulty, dictionary)
+	adjustedWords := adjustWordsForDifficulty(words, difficulty, dictionary, rng)
 	for _, word := range adjustedWords {
 		for _, closeMatch := range dictionary.CloseMatches(word) {
+			if !inAlphabet(closeMatch, puzzle.alphabet) {
+				continue
+			}
 			logger.Logf(logrus.DebugLevel, "Attempting to insert close word: %s\n", word)
-			tryInsertWord(puzzle, closeMatch, false, verbose)
+			tryInsertWord(puzzle, closeMatch, false, directions, rng, verbose)
 		}
 	}
 
 	return nil
 }
 
-func tryInsertWord(puzzle *Puzzle, word string, isSearchWord bool, verbose bool) bool {
-	gridSize := len(puzzle.grid)
+// placeSearchWords places every search word in the grid using a backtracking search.
+// When a word cannot be placed, the most recently placed word is removed and its next
+// candidate position is tried instead.  At most maxAttempts placements are tried in total.
+func placeSearchWords(puzzle *Puzzle, words []string, directions [][2]int, maxAttempts int, rng *rand.Rand,
+	verbose bool) error {
+	attempts := 0
+	stuckWord := ""
+
+	var place func(i int) bool
+	place = func(i int) bool {
+		if i == len(words) {
+			return true
+		}
+		word := words[i]
+		candidates := candidatePlacements(puzzle.grid, word, directions, rng, verbose)
+		if len(candidates) == 0 {
+			stuckWord = word
+			return false
+		}
+		for _, c := range candidates {
+			if attempts >= maxAttempts {
+				return false
+			}
+			attempts++
+			logger.Logf(logrus.DebugLevel, "Attempting to insert word: %s at (%d, %d)\n", word, c.x, c.y)
+			filled := placeWord(puzzle, word, c.x, c.y, c.dx, c.dy, true)
+			if place(i + 1) {
+				return true
+			}
+			logger.Logf(logrus.DebugLevel, "Backtracking on word: %s\n", word)
+			removeWord(puzzle, filled)
+		}
+		return false
+	}
+
+	if !place(0) {
+		if attempts >= maxAttempts {
+			return &FitError{Message: fmt.Sprintf("failed to insert all words into the grid within %d attempts",
+				maxAttempts)}
+		}
+		return &FitError{Message: "Failed to insert word into the grid: " + stuckWord}
+	}
+	logger.Logf(logrus.DebugLevel, "Inserted all search words after %d attempts\n", attempts)
+	return nil
+}
+
+// candidatePlacements returns every position the word can be placed at, in random order
+// but with the positions that overlap the most existing letters first.
+func candidatePlacements(grid Grid, word string, directions [][2]int, rng *rand.Rand,
+	verbose bool) []placedSearchWord {
+	candidates := make([]placedSearchWord, 0)
+	overlaps := make(map[placedSearchWord]int)
+
+	for _, x := range rng.Perm(len(grid)) {
+		for _, y := range rng.Perm(len(grid[x])) {
+			for _, direction := range directions {
+				dx, dy := direction[0], direction[1]
+				if canPlaceWord(grid, word, x, y, dx, dy, verbose) {
+					c := placedSearchWord{word: word, x: x, y: y, dx: dx, dy: dy}
+					candidates = append(candidates, c)
+					overlaps[c] = overlappingCells(grid, word, x, y, dx, dy)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return overlaps[candidates[i]] > overlaps[candidates[j]]
+	})
+	return candidates
+}
 
+func tryInsertWord(puzzle *Puzzle, word string, isSearchWord bool, directions [][2]int, rng *rand.Rand,
+	verbose bool) bool {
 	// Shuffle the indices randomly
-	indicesX := rand.Perm(gridSize)
-	indicesY := rand.Perm(gridSize)
+	indicesX := rng.Perm(puzzle.Width())
+	indicesY := rng.Perm(puzzle.Height())
 
 	maxOverlap := -1
 	bestX, bestY, bestDx, bestDy := -1, -1, -1, -1
 
 	for _, x := range indicesX {
 		for _, y := range indicesY {
-			for dx := -1; dx <= 1; dx++ {
-				for dy := -1; dy <= 1; dy++ {
-					if dx == 0 && dy == 0 {
-						continue
-					}
-					if canPlaceWord(puzzle.grid, word, x, y, dx, dy, verbose) {
-						overlap := overlappingCells(puzzle.grid, word, x, y, dx, dy)
-						if overlap > maxOverlap {
-							maxOverlap = overlap
-							bestX, bestY, bestDx, bestDy = x, y, dx, dy
-						}
+			for _, direction := range directions {
+				dx, dy := direction[0], direction[1]
+				if canPlaceWord(puzzle.grid, word, x, y, dx, dy, verbose) {
+					overlap := overlappingCells(puzzle.grid, word, x, y, dx, dy)
+					if overlap > maxOverlap {
+						maxOverlap = overlap
+						bestX, bestY, bestDx, bestDy = x, y, dx, dy
 					}
 				}
 			}
@@ -216,7 +474,7 @@ func tryInsertWord(puzzle *Puzzle, word string, isSearchWord bool, verbose bool)
 
 func overlappingCells(grid Grid, word string, x, y, dx, dy int) int {
 	overlapCount := 0
-	for i, r := range word {
+	for i, r := range []rune(word) {
 		newX := x + i*dx
 		newY := y + i*dy
 
@@ -228,7 +486,7 @@ func overlappingCells(grid Grid, word string, x, y, dx, dy int) int {
 }
 
 func canPlaceWord(grid Grid, word string, x, y, dx, dy int, verbose bool) bool {
-	for i, r := range word {
+	for i, r := range []rune(word) {
 		newX := x + i*dx
 		newY := y + i*dy
 
@@ -237,6 +495,11 @@ func canPlaceWord(grid Grid, word string, x, y, dx, dy int, verbose bool) bool {
 			return false
 		}
 
+		if isMaskedCell(grid, newX, newY) {
+			logger.Logf(logrus.DebugLevel, "(%d, %d) is outside the puzzle shape\n", newX, newY)
+			return false
+		}
+
 		if !isEmptyCell(grid, newX, newY) && grid[newX][newY] != r {
 			logger.Logf(logrus.DebugLevel, "(%d, %d) is not an empty cell\n", newX, newY)
 			return false
@@ -246,33 +509,120 @@ func canPlaceWord(grid Grid, word string, x, y, dx, dy int, verbose bool) bool {
 	return true
 }
 
-func placeWord(puzzle *Puzzle, word string, x, y, dx, dy int, isSearchWord bool) {
-	for i, r := range word {
+// placeWord writes the word into the grid and returns the cells that were empty
+// before, so the placement can be undone with removeWord.
+func placeWord(puzzle *Puzzle, word string, x, y, dx, dy int, isSearchWord bool) [][2]int {
+	runes := []rune(word)
+	filled := make([][2]int, 0, len(runes))
+	for i, r := range runes {
 		newX := x + i*dx
 		newY := y + i*dy
+		if isEmptyCell(puzzle.grid, newX, newY) {
+			filled = append(filled, [2]int{newX, newY})
+		}
 		puzzle.grid[newX][newY] = r
 	}
 	if isSearchWord {
-		for i, r := range word {
+		for i, r := range runes {
 			newX := x + i*dx
 			newY := y + i*dy
 			puzzle.solution[newX][newY] = r
 		}
 		puzzle.placedWords = append(puzzle.placedWords, placedSearchWord{word: word, x: x, y: y, dx: dx, dy: dy})
 	}
+	return filled
+}
+
+// removeWord undoes the most recent placeWord of a search word, clearing the cells
+// it filled.
+func removeWord(puzzle *Puzzle, filled [][2]int) {
+	for _, cell := range filled {
+		puzzle.grid[cell[0]][cell[1]] = ' '
+		puzzle.solution[cell[0]][cell[1]] = ' '
+	}
+	puzzle.placedWords = puzzle.placedWords[:len(puzzle.placedWords)-1]
 }
 
-func fillEmptyCells(grid Grid) error {
+func fillEmptyCells(grid Grid, filler Filler, rng *rand.Rand) error {
 	for i := range grid {
 		for j := range grid[i] {
 			if grid[i][j] == ' ' {
-				grid[i][j] = randomLetter()
+				grid[i][j] = filler.Letter(rng)
 			}
 		}
 	}
 	return nil
 }
 
-func randomLetter() rune {
-	return rune('A' + rand.Intn(26))
+// ensureUniqueWords makes sure each search word can only be found at the location it
+// was placed.  Whenever a word also appears somewhere else, one of the filler or decoy
+// letters in that extra occurrence is replaced with a new random letter.
+func ensureUniqueWords(puzzle *Puzzle, rng *rand.Rand) error {
+	words := puzzle.Words()
+	for round := 0; round < maxRepairRounds; round++ {
+		solutions := Solve(puzzle.grid, words)
+		repaired := false
+		for _, word := range words {
+			for _, location := range solutions[word] {
+				if puzzle.isPlacedAt(location) {
+					continue
+				}
+				cells := puzzle.replaceableCells(location)
+				if len(cells) == 0 {
+					return fmt.Errorf("search word '%s' appears more than once and cannot be repaired "+
+						"(set allow_duplicates to permit this)", word)
+				}
+				cell := cells[rng.Intn(len(cells))]
+				logger.Logf(logrus.DebugLevel, "Word %s also found at %s, replacing letter at (%d, %d)\n",
+					word, location, cell[0], cell[1])
+				puzzle.grid[cell[0]][cell[1]] = puzzle.filler.Letter(rng)
+				repaired = true
+			}
+			if repaired {
+				// Re-solve since the changed letter may affect other words
+				break
+			}
+		}
+		if !repaired {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to make every search word unique after %d attempts", maxRepairRounds)
+}
+
+// isPlacedAt reports whether the location covers the same cells as one of the placed
+// search words, read in either direction.
+func (p Puzzle) isPlacedAt(location WordLocation) bool {
+	length := len([]rune(normalizeWord(location.Word)))
+	endX := location.X + (length-1)*location.DX
+	endY := location.Y + (length-1)*location.DY
+	for _, placedWord := range p.placedWords {
+		if placedWord.word != location.Word {
+			continue
+		}
+		if placedWord.x == location.X && placedWord.y == location.Y &&
+			placedWord.dx == location.DX && placedWord.dy == location.DY {
+			return true
+		}
+		if placedWord.x == endX && placedWord.y == endY &&
+			placedWord.dx == -location.DX && placedWord.dy == -location.DY {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceableCells returns the cells of a location that are not part of any search
+// word or the hidden message, so they can be changed without breaking the solution.
+func (p Puzzle) replaceableCells(location WordLocation) [][2]int {
+	cells := make([][2]int, 0)
+	length := len([]rune(normalizeWord(location.Word)))
+	for i := 0; i < length; i++ {
+		x := location.X + i*location.DX
+		y := location.Y + i*location.DY
+		if p.solution[x][y] == ' ' && !p.isMessageCell(x, y) {
+			cells = append(cells, [2]int{x, y})
+		}
+	}
+	return cells
 }
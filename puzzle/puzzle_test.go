@@ -1,6 +1,9 @@
 package puzzle
 
 import (
+	"errors"
+	"math/rand"
+	"os"
 	"testing"
 )
 
@@ -17,7 +20,7 @@ func TestFillEmptyCells(t *testing.T) {
 	originalGrid := make(Grid, len(grid))
 	copy(originalGrid, grid)
 
-	err := fillEmptyCells(grid)
+	err := fillEmptyCells(grid, uniformWeights([]rune(latinLetters)), rand.New(rand.NewSource(1)))
 	if err != nil {
 		t.Errorf("fillEmptyCells returned an unexpected error: %v", err)
 	}
@@ -43,8 +46,258 @@ func isValidLetter(r rune) bool {
 }
 
 func TestRandomLetter(t *testing.T) {
-	r := randomLetter()
+	r := uniformWeights([]rune(latinLetters)).Letter(rand.New(rand.NewSource(1)))
 	if r < 'A' || r > 'Z' {
-		t.Errorf("randomLetter() = %v; want a value between 'A' and 'Z'", r)
+		t.Errorf("uniformWeights(latinLetters).Letter() = %c; want a value between 'A' and 'Z'", r)
+	}
+}
+
+// writeTestDictionary creates a small temporary dictionary file and returns its path.
+func writeTestDictionary(t *testing.T) string {
+	tmpFile, err := os.CreateTemp("", "test_dict_*.txt")
+	if err != nil {
+		t.Fatalf("Failed to create temporary dictionary file: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+
+	if _, err := tmpFile.WriteString("CAT\nDOG\nBIRD\nFISH\nHORSE\nMOUSE\nTIGER\nLION\nA\nE\n"); err != nil {
+		t.Fatalf("Failed to write temporary dictionary file: %v", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		t.Fatalf("Failed to close temporary dictionary file: %v", err)
+	}
+	return tmpFile.Name()
+}
+
+func TestGeneratePuzzleSeedIsDeterministic(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Size: 10, Difficulty: 5, Seed: 42,
+		Words: WordEntries([]string{"APPLE", "BANANA", "CHERRY", "GRAPE"})}
+
+	first, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+	second, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+
+	if first.Seed() != 42 {
+		t.Errorf("Expected seed 42, got %d", first.Seed())
+	}
+	for x := range first.grid {
+		for y := range first.grid[x] {
+			if first.grid[x][y] != second.grid[x][y] {
+				t.Fatalf("Puzzles generated with the same seed differ at (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+func TestPlaceSearchWordsBacktracks(t *testing.T) {
+	// Four 4-letter words with no letters in common only fit as four parallel lines,
+	// which a single greedy pass rarely finds.
+	words := []string{"ABCD", "EFGH", "IJKL", "MNOP"}
+	for seed := int64(1); seed <= 20; seed++ {
+		puzzle := createPuzzle(4, 4)
+		err := placeSearchWords(&puzzle, words, allDirections, defaultMaxAttempts, rand.New(rand.NewSource(seed)), false)
+		if err != nil {
+			t.Fatalf("placeSearchWords with seed %d returned error: %v", seed, err)
+		}
+		if len(puzzle.placedWords) != len(words) {
+			t.Fatalf("Expected %d placed words, got %d", len(words), len(puzzle.placedWords))
+		}
+		for x := range puzzle.grid {
+			for y := range puzzle.grid[x] {
+				if isEmptyCell(puzzle.grid, x, y) {
+					t.Fatalf("Expected a full grid with seed %d, (%d,%d) is empty", seed, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestPlaceSearchWordsUnicode(t *testing.T) {
+	// Words of multi-byte letters fill one cell per letter
+	words := []string{"ΑΛΦΑ", "ΒΗΤΑ", "ÄÖÜẞ", "ЖЁЛТ"}
+	puzzle := createPuzzle(4, 4)
+	err := placeSearchWords(&puzzle, words, allDirections, defaultMaxAttempts, rand.New(rand.NewSource(1)), false)
+	if err != nil {
+		t.Fatalf("placeSearchWords returned error: %v", err)
+	}
+	for _, word := range words {
+		if locations := findWord(puzzle.grid, word); len(locations) != 1 {
+			t.Errorf("Expected %s to appear once in the grid, found %d", word, len(locations))
+		}
+	}
+}
+
+func TestPlaceSearchWordsAttemptBudget(t *testing.T) {
+	// Five 2-letter words with distinct letters cannot fit in a 2x2 grid.
+	words := []string{"AB", "CD", "EF", "GH", "IJ"}
+	puzzle := createPuzzle(2, 2)
+	err := placeSearchWords(&puzzle, words, allDirections, 50, rand.New(rand.NewSource(1)), false)
+	var fitErr *FitError
+	if !errors.As(err, &fitErr) {
+		t.Fatalf("Expected a FitError placing words that cannot fit, got %v", err)
+	}
+	if len(puzzle.placedWords) != 0 {
+		t.Errorf("Expected all placements to be undone, got %d placed words", len(puzzle.placedWords))
+	}
+	for x := range puzzle.grid {
+		for y := range puzzle.grid[x] {
+			if !isEmptyCell(puzzle.grid, x, y) || puzzle.solution[x][y] != ' ' {
+				t.Errorf("Expected (%d,%d) to be cleared after backtracking", x, y)
+			}
+		}
+	}
+}
+
+func TestGeneratePuzzleRectangular(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Width: 15, Height: 6, Difficulty: 3, Seed: 7,
+		Words: WordEntries([]string{"ELEPHANT", "GIRAFFE", "ZEBRA", "HIPPOPOTAMUS"})}
+
+	puzzle, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+	if puzzle.Width() != 15 || puzzle.Height() != 6 {
+		t.Errorf("Expected a 15x6 grid, got %dx%d", puzzle.Width(), puzzle.Height())
+	}
+	if len(puzzle.placedWords) != len(config.Words) {
+		t.Errorf("Expected %d placed words, got %d", len(config.Words), len(puzzle.placedWords))
+	}
+}
+
+func TestGeneratePuzzleRespectsDirections(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Size: 10, Difficulty: 9, Seed: 3, Directions: []string{"right", "down"},
+		Words: WordEntries([]string{"APPLE", "BANANA", "CHERRY", "GRAPE", "LEMON", "MELON"})}
+
+	puzzle, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+	for _, word := range puzzle.placedWords {
+		if !(word.dx == 1 && word.dy == 0) && !(word.dx == 0 && word.dy == 1) {
+			t.Errorf("Word %s was placed in direction (%d, %d)", word.word, word.dx, word.dy)
+		}
+	}
+
+	config.Directions = []string{"sideways"}
+	if _, err := GeneratePuzzle(config, dictionaryPath, false); err == nil {
+		t.Errorf("Expected an error for an invalid direction")
+	}
+}
+
+func TestEnsureUniqueWordsRepairsFiller(t *testing.T) {
+	puzzle := createPuzzle(5, 3)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	// Filler letters that spell CAT a second time, backwards on the last row
+	for x, r := range "XXTAC" {
+		puzzle.grid[x][1] = 'X'
+		puzzle.grid[x][2] = r
+	}
+	puzzle.grid[3][0], puzzle.grid[4][0] = 'X', 'X'
+
+	if err := ensureUniqueWords(&puzzle, rand.New(rand.NewSource(1))); err != nil {
+		t.Fatalf("ensureUniqueWords returned error: %v", err)
+	}
+	if problems := Verify(puzzle.grid, []string{"CAT"}); len(problems) != 0 {
+		t.Errorf("Expected CAT to appear once, got %v", problems)
+	}
+	if string([]rune{puzzle.grid[0][0], puzzle.grid[1][0], puzzle.grid[2][0]}) != "CAT" {
+		t.Errorf("ensureUniqueWords changed the placed search word")
+	}
+}
+
+func TestEnsureUniqueWordsCannotRepairSearchWords(t *testing.T) {
+	puzzle := createPuzzle(3, 2)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	placeWord(&puzzle, "CAT", 0, 1, 1, 0, true)
+	puzzle.placedWords = puzzle.placedWords[:1]
+
+	if err := ensureUniqueWords(&puzzle, rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("Expected an error when a duplicate is made of search word letters")
+	}
+}
+
+func TestGeneratePuzzleWordsAreUnique(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Size: 8, Difficulty: 9,
+		Words: WordEntries([]string{"CAT", "DOG", "BIRD", "FISH", "LION"})}
+
+	for seed := int64(1); seed <= 20; seed++ {
+		config.Seed = seed
+		puzzle, err := GeneratePuzzle(config, dictionaryPath, false)
+		if err != nil {
+			t.Fatalf("GeneratePuzzle with seed %d returned error: %v", seed, err)
+		}
+		if problems := Verify(puzzle.grid, config.DisplayWords()); len(problems) != 0 {
+			t.Errorf("Expected every word to appear once with seed %d, got %v", seed, problems)
+		}
+	}
+}
+
+func TestGeneratePuzzleDisplayWords(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Size: 10, Difficulty: 5, Seed: 3, Words: []WordEntry{
+		{Display: "O'Brien"}, {Display: "T-Rex"}, {Display: "Shih Tzu"}, {Display: "Dr. Who", Grid: "DOCTORWHO"}}}
+	puzzle, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+
+	expected := map[string]string{"O'Brien": "OBRIEN", "T-Rex": "TREX", "Shih Tzu": "SHIHTZU", "Dr. Who": "DOCTORWHO"}
+	for display, word := range expected {
+		if got := puzzle.gridWord(display); got != word {
+			t.Errorf("Expected %q to be placed as %q, got %q", display, word, got)
+		}
+	}
+	if problems := Verify(puzzle.grid, puzzle.Words()); len(problems) != 0 {
+		t.Errorf("Expected every word to be found once, got %v", problems)
+	}
+}
+
+func TestGeneratePuzzleStripsDiacritics(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Size: 10, Difficulty: 5, Seed: 3, Words: WordEntries([]string{"Café", "Crème", "Thé"})}
+	puzzle, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+
+	if got := puzzle.gridWord("Café"); got != "CAFE" {
+		t.Errorf("Expected Café to be placed as CAFE, got %q", got)
+	}
+	if string(puzzle.alphabet) != latinLetters {
+		t.Errorf("Expected the filler to use A-Z, got %s", string(puzzle.alphabet))
+	}
+	for x := range puzzle.grid {
+		for y := range puzzle.grid[x] {
+			if !isValidLetter(puzzle.grid[x][y]) {
+				t.Errorf("Expected only A-Z in the grid, got %c at (%d,%d)", puzzle.grid[x][y], x, y)
+			}
+		}
+	}
+}
+
+func TestGeneratePuzzleFixedFillHasNoDecoys(t *testing.T) {
+	dictionaryPath := writeTestDictionary(t)
+	config := &PuzzleConfig{Size: 10, Difficulty: 9, Seed: 5, Fill: "fixed",
+		Words: WordEntries([]string{"APPLE", "BANANA", "CHERRY"})}
+	puzzle, err := GeneratePuzzle(config, dictionaryPath, false)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned error: %v", err)
+	}
+
+	for x := range puzzle.grid {
+		for y := range puzzle.grid[x] {
+			if puzzle.solution[x][y] == ' ' && puzzle.grid[x][y] != defaultFillCharacter {
+				t.Errorf("Expected filler at (%d,%d), got %c", x, y, puzzle.grid[x][y])
+			}
+		}
 	}
 }
@@ -61,7 +61,8 @@ func TestGeneratePuzzle_RequiresDict(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := GeneratePuzzle(tc.size, tc.words, 0, tc.difficulty, "../dict-en.txt", false)
+			config := &PuzzleConfig{Size: tc.size, Words: WordEntries(tc.words), Difficulty: tc.difficulty}
+			_, err := GeneratePuzzle(config, "../dict-en.txt", false)
 			if tc.expectedError && err == nil {
 				t.Errorf("Expected an error but didn't get one")
 			}
@@ -0,0 +1,431 @@
+package puzzle
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	RegisterRenderer("png", newRasterRenderer)
+	RegisterRenderer("jpeg", newRasterRenderer)
+	RegisterRenderer("jpg", newRasterRenderer)
+}
+
+// GenerateImage draws the puzzle page (title, grid and word list) to a PNG or JPEG
+// file, chosen by the extension of outputFile.  When showSolution is true a line is
+// drawn through each search word and the hidden message is revealed.  Zero options
+// are replaced by the defaults: 150 DPI and JPEG quality 90.
+func GenerateImage(puzzle Puzzle, title string, words []string, columns int, page PageSetup, outputFile string,
+	showSolution bool, options RenderOptions) error {
+	layout, err := NewPageLayout(page, puzzle, len(words), columns)
+	if err != nil {
+		return err
+	}
+	r := newRasterRenderer(options)
+	if err := r.NewPage(layout); err != nil {
+		return err
+	}
+	r.DrawTitle(title)
+	if showSolution {
+		r.DrawSolution(puzzle)
+	} else {
+		r.DrawGrid(puzzle)
+	}
+	if clues := puzzle.Clues(); len(clues) > 0 {
+		r.DrawClueList(clues, showSolution)
+	} else {
+		r.DrawWordList(words)
+	}
+	return r.Save(outputFile)
+}
+
+// rasterRenderer draws each puzzle page as an image.
+type rasterRenderer struct {
+	options RenderOptions
+	pages   []*rasterCanvas
+	layout  PageLayout
+}
+
+func newRasterRenderer(options RenderOptions) Renderer {
+	if options.DPI <= 0 {
+		options.DPI = 150
+	}
+	if options.Quality <= 0 {
+		options.Quality = 90
+	}
+	if options.Font != "" || options.GridFont != "" {
+		logger.Warnf("PNG and JPEG output uses its built-in font; font and grid_font only apply to PDF output")
+	}
+	return &rasterRenderer{options: options}
+}
+
+func (r *rasterRenderer) canvas() *rasterCanvas {
+	return r.pages[len(r.pages)-1]
+}
+
+func (r *rasterRenderer) NewPage(layout PageLayout) error {
+	r.layout = layout
+	canvas := newRasterCanvas(layout.PageWidth, layout.PageHeight, r.options.DPI)
+	if r.options.Background != "" {
+		if err := canvas.drawBackground(r.options.Background); err != nil {
+			return err
+		}
+		canvas.fillRect(layout.LeftMargin, layout.TopMargin, layout.ContentWidth(), layout.ContentHeight(),
+			color.White)
+	}
+	r.pages = append(r.pages, canvas)
+	return nil
+}
+
+func (r *rasterRenderer) DrawTitle(title string) {
+	r.canvas().drawText(title, 0, r.layout.TitleY, r.layout.PageWidth, r.layout.TitleHeight, titleFontSize,
+		true, color.Black)
+}
+
+func (r *rasterRenderer) DrawGrid(puzzle Puzzle) {
+	r.drawLetters(puzzle)
+	r.drawSeed(puzzle.seed)
+}
+
+func (r *rasterRenderer) DrawWordList(words []string) {
+	for i, word := range words {
+		x, y := r.layout.WordPosition(i)
+		r.canvas().drawText(word, x, y, r.layout.WordColumnWidth, r.layout.WordCellHeight, r.layout.WordFontSize, false,
+			color.Black)
+	}
+}
+
+// DrawClueList draws the clues numbered and left aligned.
+func (r *rasterRenderer) DrawClueList(clues []Clue, withAnswers bool) {
+	for i, text := range clueText(clues, withAnswers) {
+		text = fmt.Sprintf("%d. %s", i+1, text)
+		x, y := r.layout.WordPosition(i)
+		r.canvas().drawText(text, x, y, r.canvas().textWidth(text, r.layout.WordFontSize), r.layout.WordCellHeight,
+			r.layout.WordFontSize, false, color.Black)
+	}
+}
+
+// DrawSolution draws the whole grid with a line through each search word, underneath
+// the letters, and the hidden message just above the seed.
+func (r *rasterRenderer) DrawSolution(puzzle Puzzle) {
+	for _, location := range puzzle.Locations() {
+		startX, startY, endX, endY := r.layout.WordEnds(location)
+		r.canvas().drawLine(startX, startY, endX, endY, 1, color.Gray{Y: 192})
+	}
+	r.drawLetters(puzzle)
+
+	if puzzle.hiddenMessage != "" {
+		r.canvas().drawText("Hidden message: "+puzzle.hiddenMessage, r.layout.LeftMargin, r.layout.MessageY(),
+			r.layout.ContentWidth(), 10, messageFontSize, true, color.Black)
+	}
+	r.drawSeed(puzzle.seed)
+}
+
+func (r *rasterRenderer) drawLetters(puzzle Puzzle) {
+	for y := 0; y < puzzle.Height(); y++ {
+		for x := 0; x < puzzle.Width(); x++ {
+			left, top := r.layout.CellPosition(x, y)
+			r.canvas().drawText(cellText(puzzle.grid[x][y]), left, top, r.layout.CellSize, r.layout.CellSize,
+				r.layout.GridFontSize(), true, color.Black)
+		}
+	}
+}
+
+// drawSeed prints the seed right aligned at the bottom of the page.
+func (r *rasterRenderer) drawSeed(seed int64) {
+	text := fmt.Sprintf("Seed: %d", seed)
+	width := r.canvas().textWidth(text, seedFontSize)
+	r.canvas().drawText(text, r.layout.LeftMargin+r.layout.ContentWidth()-width, r.layout.SeedY(), width, 5,
+		seedFontSize, false, color.Gray{Y: 128})
+}
+
+// Save writes the first page to outputFile and any later pages next to it, numbered
+// from 2 (puzzle.png, puzzle_2.png, ...).
+func (r *rasterRenderer) Save(outputFile string) error {
+	ext := filepath.Ext(outputFile)
+	for i, page := range r.pages {
+		fileName := outputFile
+		if i > 0 {
+			fileName = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(outputFile, ext), i+1, ext)
+		}
+		if err := page.save(fileName, r.options.Quality); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rasterCanvas draws onto an image using positions and sizes in mm.
+type rasterCanvas struct {
+	img   *image.RGBA
+	scale float64 // pixels per mm
+	err   error   // the first text the bitmap font cannot draw
+}
+
+func newRasterCanvas(width, height, dpi float64) *rasterCanvas {
+	scale := dpi / 25.4
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Round(width*scale)), int(math.Round(height*scale))))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	return &rasterCanvas{img: img, scale: scale}
+}
+
+func (c *rasterCanvas) px(mm float64) int {
+	return int(math.Round(mm * c.scale))
+}
+
+func (c *rasterCanvas) fillRect(x, y, width, height float64, col color.Color) {
+	rect := image.Rect(c.px(x), c.px(y), c.px(x+width), c.px(y+height))
+	draw.Draw(c.img, rect, image.NewUniform(col), image.Point{}, draw.Src)
+}
+
+// drawLine draws a line with round ends by stamping a disc along it.
+func (c *rasterCanvas) drawLine(x1, y1, x2, y2, width float64, col color.Color) {
+	radius := width * c.scale / 2
+	startX, startY := x1*c.scale, y1*c.scale
+	endX, endY := x2*c.scale, y2*c.scale
+	steps := int(math.Ceil(math.Hypot(endX-startX, endY-startY))) + 1
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		c.fillDisc(startX+t*(endX-startX), startY+t*(endY-startY), radius, col)
+	}
+}
+
+func (c *rasterCanvas) fillDisc(cx, cy, radius float64, col color.Color) {
+	for y := int(math.Floor(cy - radius)); y <= int(math.Ceil(cy+radius)); y++ {
+		for x := int(math.Floor(cx - radius)); x <= int(math.Ceil(cx+radius)); x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= radius*radius {
+				c.img.Set(x, y, col)
+			}
+		}
+	}
+}
+
+// dotSize returns the size in mm of one dot of the bitmap font at a font size in
+// points.  The 7 dot high capitals are about 70% of the font size.
+func dotSize(fontSize float64) float64 {
+	return fontSize * 0.7 * 25.4 / 72 / 7
+}
+
+// textWidth returns the width in mm of text drawn at the font size.
+func (c *rasterCanvas) textWidth(text string, fontSize float64) float64 {
+	length := len([]rune(text))
+	if length == 0 {
+		return 0
+	}
+	return float64(6*length-1) * dotSize(fontSize)
+}
+
+// drawText draws text centered in the box at (x, y) with the given width and height.
+// Text with characters the bitmap font does not have is not drawn, and saving the
+// canvas fails instead.
+func (c *rasterCanvas) drawText(text string, x, y, width, height, fontSize float64, bold bool, col color.Color) {
+	for _, r := range text {
+		if _, ok := glyphFor(r); !ok {
+			if c.err == nil {
+				c.err = fmt.Errorf("PNG and JPEG output can only draw ASCII text, not %q in %q; "+
+					"use PDF, SVG or HTML output instead", r, text)
+			}
+			return
+		}
+	}
+
+	dot := dotSize(fontSize)
+	left := x + (width-c.textWidth(text, fontSize))/2
+	top := y + (height-7*dot)/2
+	for i, r := range []rune(text) {
+		glyph, _ := glyphFor(r)
+		glyphX := left + float64(6*i)*dot
+		for row := 0; row < 7; row++ {
+			for column := 0; column < 5; column++ {
+				if glyph[row]&(0x10>>column) == 0 {
+					continue
+				}
+				dotWidth := dot
+				if bold {
+					dotWidth = dot * 1.4
+				}
+				c.fillRect(glyphX+float64(column)*dot, top+float64(row)*dot, dotWidth, dot, col)
+			}
+		}
+	}
+}
+
+// drawBackground tiles the image around the border of the page, like the PDF does.
+func (c *rasterCanvas) drawBackground(imagePath string) error {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	tile, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("failed to load background image %s: %v", imagePath, err)
+	}
+
+	imgWidth := float64(tile.Bounds().Dx()) / 10
+	imgHeight := float64(tile.Bounds().Dy()) / 10
+	pageWidth := float64(c.img.Bounds().Dx()) / c.scale
+	pageHeight := float64(c.img.Bounds().Dy()) / c.scale
+
+	for x := 0.0; x < pageWidth; x += imgWidth {
+		c.drawImage(tile, x, 0, imgWidth, imgHeight)
+		c.drawImage(tile, x, pageHeight-imgHeight, imgWidth, imgHeight)
+	}
+	for y := imgHeight; y < pageHeight-imgHeight; y += imgHeight {
+		c.drawImage(tile, 0, y, imgWidth, imgHeight)
+		c.drawImage(tile, pageWidth-imgWidth, y, imgWidth, imgHeight)
+	}
+	return nil
+}
+
+// drawImage scales src (nearest neighbour) into the box at (x, y).
+func (c *rasterCanvas) drawImage(src image.Image, x, y, width, height float64) {
+	rect := image.Rect(c.px(x), c.px(y), c.px(x+width), c.px(y+height))
+	bounds := src.Bounds()
+	for py := rect.Min.Y; py < rect.Max.Y; py++ {
+		srcY := bounds.Min.Y + (py-rect.Min.Y)*bounds.Dy()/rect.Dy()
+		for px := rect.Min.X; px < rect.Max.X; px++ {
+			srcX := bounds.Min.X + (px-rect.Min.X)*bounds.Dx()/rect.Dx()
+			c.img.Set(px, py, src.At(srcX, srcY))
+		}
+	}
+}
+
+func (c *rasterCanvas) save(outputFile string, quality int) error {
+	if c.err != nil {
+		return c.err
+	}
+	ext := strings.ToLower(filepath.Ext(outputFile))
+	if ext != ".png" && ext != ".jpg" && ext != ".jpeg" {
+		return fmt.Errorf("unsupported image format %q", filepath.Ext(outputFile))
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if ext == ".png" {
+		return png.Encode(file, c.img)
+	}
+	return jpeg.Encode(file, c.img, &jpeg.Options{Quality: quality})
+}
+
+// glyphFor returns the bitmap for a character, and false for characters the font
+// does not have.
+func glyphFor(r rune) ([7]byte, bool) {
+	if r >= ' ' && int(r-' ') < len(bitmapFont) {
+		return bitmapFont[r-' '], true
+	}
+	return [7]byte{}, false
+}
+
+// bitmapFont is a 5x7 font for the printable ASCII characters.  Each glyph is seven
+// rows from top to bottom, with the leftmost dot in bit 4.
+var bitmapFont = [...][7]byte{
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // ' '
+	{0x04, 0x04, 0x04, 0x04, 0x00, 0x00, 0x04}, // '!'
+	{0x0A, 0x0A, 0x0A, 0x00, 0x00, 0x00, 0x00}, // '"'
+	{0x0A, 0x0A, 0x1F, 0x0A, 0x1F, 0x0A, 0x0A}, // '#'
+	{0x04, 0x0F, 0x14, 0x0E, 0x05, 0x1E, 0x04}, // '$'
+	{0x18, 0x19, 0x02, 0x04, 0x08, 0x13, 0x03}, // '%'
+	{0x0C, 0x12, 0x14, 0x08, 0x15, 0x12, 0x0D}, // '&'
+	{0x0C, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00}, // '\''
+	{0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02}, // '('
+	{0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08}, // ')'
+	{0x00, 0x04, 0x15, 0x0E, 0x15, 0x04, 0x00}, // '*'
+	{0x00, 0x04, 0x04, 0x1F, 0x04, 0x04, 0x00}, // '+'
+	{0x00, 0x00, 0x00, 0x00, 0x0C, 0x04, 0x08}, // ','
+	{0x00, 0x00, 0x00, 0x1F, 0x00, 0x00, 0x00}, // '-'
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C}, // '.'
+	{0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00}, // '/'
+	{0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E}, // '0'
+	{0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E}, // '1'
+	{0x0E, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1F}, // '2'
+	{0x1F, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0E}, // '3'
+	{0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02}, // '4'
+	{0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E}, // '5'
+	{0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E}, // '6'
+	{0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08}, // '7'
+	{0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E}, // '8'
+	{0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C}, // '9'
+	{0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x0C, 0x00}, // ':'
+	{0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x04, 0x08}, // ';'
+	{0x02, 0x04, 0x08, 0x10, 0x08, 0x04, 0x02}, // '<'
+	{0x00, 0x00, 0x1F, 0x00, 0x1F, 0x00, 0x00}, // '='
+	{0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x08}, // '>'
+	{0x0E, 0x11, 0x01, 0x02, 0x04, 0x00, 0x04}, // '?'
+	{0x0E, 0x11, 0x01, 0x0D, 0x15, 0x15, 0x0E}, // '@'
+	{0x0E, 0x11, 0x11, 0x11, 0x1F, 0x11, 0x11}, // 'A'
+	{0x1E, 0x11, 0x11, 0x1E, 0x11, 0x11, 0x1E}, // 'B'
+	{0x0E, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0E}, // 'C'
+	{0x1C, 0x12, 0x11, 0x11, 0x11, 0x12, 0x1C}, // 'D'
+	{0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x1F}, // 'E'
+	{0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x10}, // 'F'
+	{0x0E, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0F}, // 'G'
+	{0x11, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11}, // 'H'
+	{0x0E, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E}, // 'I'
+	{0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0C}, // 'J'
+	{0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11}, // 'K'
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F}, // 'L'
+	{0x11, 0x1B, 0x15, 0x15, 0x11, 0x11, 0x11}, // 'M'
+	{0x11, 0x11, 0x19, 0x15, 0x13, 0x11, 0x11}, // 'N'
+	{0x0E, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E}, // 'O'
+	{0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10, 0x10}, // 'P'
+	{0x0E, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0D}, // 'Q'
+	{0x1E, 0x11, 0x11, 0x1E, 0x14, 0x12, 0x11}, // 'R'
+	{0x0F, 0x10, 0x10, 0x0E, 0x01, 0x01, 0x1E}, // 'S'
+	{0x1F, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04}, // 'T'
+	{0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E}, // 'U'
+	{0x11, 0x11, 0x11, 0x11, 0x11, 0x0A, 0x04}, // 'V'
+	{0x11, 0x11, 0x11, 0x15, 0x15, 0x15, 0x0A}, // 'W'
+	{0x11, 0x11, 0x0A, 0x04, 0x0A, 0x11, 0x11}, // 'X'
+	{0x11, 0x11, 0x11, 0x0A, 0x04, 0x04, 0x04}, // 'Y'
+	{0x1F, 0x01, 0x02, 0x04, 0x08, 0x10, 0x1F}, // 'Z'
+	{0x0E, 0x08, 0x08, 0x08, 0x08, 0x08, 0x0E}, // '['
+	{0x00, 0x10, 0x08, 0x04, 0x02, 0x01, 0x00}, // '\\'
+	{0x0E, 0x02, 0x02, 0x02, 0x02, 0x02, 0x0E}, // ']'
+	{0x04, 0x0A, 0x11, 0x00, 0x00, 0x00, 0x00}, // '^'
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1F}, // '_'
+	{0x08, 0x04, 0x02, 0x00, 0x00, 0x00, 0x00}, // '`'
+	{0x00, 0x00, 0x0E, 0x01, 0x0F, 0x11, 0x0F}, // 'a'
+	{0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x1E}, // 'b'
+	{0x00, 0x00, 0x0E, 0x10, 0x10, 0x11, 0x0E}, // 'c'
+	{0x01, 0x01, 0x0D, 0x13, 0x11, 0x11, 0x0F}, // 'd'
+	{0x00, 0x00, 0x0E, 0x11, 0x1F, 0x10, 0x0E}, // 'e'
+	{0x06, 0x09, 0x08, 0x1C, 0x08, 0x08, 0x08}, // 'f'
+	{0x00, 0x0F, 0x11, 0x11, 0x0F, 0x01, 0x0E}, // 'g'
+	{0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x11}, // 'h'
+	{0x04, 0x00, 0x0C, 0x04, 0x04, 0x04, 0x0E}, // 'i'
+	{0x02, 0x00, 0x06, 0x02, 0x02, 0x12, 0x0C}, // 'j'
+	{0x10, 0x10, 0x12, 0x14, 0x18, 0x14, 0x12}, // 'k'
+	{0x0C, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E}, // 'l'
+	{0x00, 0x00, 0x1A, 0x15, 0x15, 0x11, 0x11}, // 'm'
+	{0x00, 0x00, 0x16, 0x19, 0x11, 0x11, 0x11}, // 'n'
+	{0x00, 0x00, 0x0E, 0x11, 0x11, 0x11, 0x0E}, // 'o'
+	{0x00, 0x00, 0x1E, 0x11, 0x1E, 0x10, 0x10}, // 'p'
+	{0x00, 0x00, 0x0D, 0x13, 0x0F, 0x01, 0x01}, // 'q'
+	{0x00, 0x00, 0x16, 0x19, 0x10, 0x10, 0x10}, // 'r'
+	{0x00, 0x00, 0x0E, 0x10, 0x0E, 0x01, 0x1E}, // 's'
+	{0x08, 0x08, 0x1C, 0x08, 0x08, 0x09, 0x06}, // 't'
+	{0x00, 0x00, 0x11, 0x11, 0x11, 0x13, 0x0D}, // 'u'
+	{0x00, 0x00, 0x11, 0x11, 0x11, 0x0A, 0x04}, // 'v'
+	{0x00, 0x00, 0x11, 0x11, 0x15, 0x15, 0x0A}, // 'w'
+	{0x00, 0x00, 0x11, 0x0A, 0x04, 0x0A, 0x11}, // 'x'
+	{0x00, 0x00, 0x11, 0x11, 0x0F, 0x01, 0x0E}, // 'y'
+	{0x00, 0x00, 0x1F, 0x02, 0x04, 0x08, 0x1F}, // 'z'
+	{0x02, 0x04, 0x04, 0x08, 0x04, 0x04, 0x02}, // '{'
+	{0x04, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04}, // '|'
+	{0x08, 0x04, 0x04, 0x02, 0x04, 0x04, 0x08}, // '}'
+	{0x00, 0x00, 0x00, 0x0D, 0x12, 0x00, 0x00}, // '~'
+}
@@ -0,0 +1,103 @@
+package puzzle
+
+import (
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateImage(t *testing.T) {
+	puzzle := createPuzzle(3, 3)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	for y := 1; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			puzzle.grid[x][y] = 'X'
+		}
+	}
+
+	outputDir := t.TempDir()
+	for _, name := range []string{"puzzle.png", "puzzle.jpg"} {
+		outputFile := filepath.Join(outputDir, name)
+		err := GenerateImage(puzzle, "Test", []string{"CAT"}, 0, PageSetup{}, outputFile, false, RenderOptions{DPI: 72})
+		if err != nil {
+			t.Fatalf("GenerateImage returned error: %v", err)
+		}
+		img := decodeImage(t, outputFile)
+		// A4 at 72 DPI
+		if img.Bounds().Dx() != 595 || img.Bounds().Dy() != 842 {
+			t.Errorf("%s: expected a 595x842 image, got %v", name, img.Bounds())
+		}
+		if !hasDarkPixel(img, img.Bounds()) {
+			t.Errorf("%s: expected the page to have some text", name)
+		}
+	}
+}
+
+func TestGenerateImageSolution(t *testing.T) {
+	puzzle := createPuzzle(3, 1)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	outputFile := filepath.Join(t.TempDir(), "solution.png")
+	err := GenerateImage(puzzle, "", nil, 0, PageSetup{}, outputFile, true, RenderOptions{DPI: 72})
+	if err != nil {
+		t.Fatalf("GenerateImage returned error: %v", err)
+	}
+	img := decodeImage(t, outputFile)
+
+	// The strike line is light grey, between the letters of the word
+	layout, _ := NewPageLayout(PageSetup{}, puzzle, 0, 0)
+	x, y := layout.CellCenter(0, 0)
+	scale := 72 / 25.4
+	r, g, b, _ := img.At(int((x+layout.CellSize/2)*scale), int(y*scale)).RGBA()
+	if r>>8 != 192 || g>>8 != 192 || b>>8 != 192 {
+		t.Errorf("Expected the solution line between the first two letters")
+	}
+}
+
+func TestGenerateImageUnknownFormat(t *testing.T) {
+	puzzle := createPuzzle(3, 3)
+	outputFile := filepath.Join(t.TempDir(), "puzzle.bmp")
+	if err := GenerateImage(puzzle, "", nil, 0, PageSetup{}, outputFile, false, RenderOptions{}); err == nil {
+		t.Errorf("Expected an error for an unsupported image format")
+	}
+}
+
+func TestGenerateImageNonASCII(t *testing.T) {
+	puzzle := createPuzzle(3, 1)
+	placeWord(&puzzle, "AÑO", 0, 0, 1, 0, true)
+	outputFile := filepath.Join(t.TempDir(), "puzzle.png")
+	if err := GenerateImage(puzzle, "", []string{"AÑO"}, 0, PageSetup{}, outputFile, false, RenderOptions{}); err == nil {
+		t.Errorf("Expected an error for letters the image font does not have")
+	}
+	if _, err := os.Stat(outputFile); err == nil {
+		t.Errorf("Expected no image to be written")
+	}
+}
+
+func decodeImage(t *testing.T, fileName string) image.Image {
+	t.Helper()
+	file, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("Failed to open image: %v", err)
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		t.Fatalf("Failed to decode image: %v", err)
+	}
+	return img
+}
+
+func hasDarkPixel(img image.Image, rect image.Rectangle) bool {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y < 64 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package puzzle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Renderer draws puzzle pages in one output format.  Where everything goes on the
+// page comes from the PageLayout passed to NewPage, so a renderer only has to know
+// how to draw.
+type Renderer interface {
+	// NewPage starts a new page laid out by layout.
+	NewPage(layout PageLayout) error
+	DrawTitle(title string)
+	// DrawGrid draws the puzzle grid, with the seed at the bottom of the page.
+	DrawGrid(puzzle Puzzle)
+	DrawWordList(words []string)
+	// DrawClueList draws numbered clues where the word list goes, each followed by its
+	// answer when withAnswers is true.
+	DrawClueList(clues []Clue, withAnswers bool)
+	// DrawSolution draws the grid with each search word marked, the hidden message if
+	// there is one, and the seed at the bottom of the page.
+	DrawSolution(puzzle Puzzle)
+	// Save writes every page to outputFile.
+	Save(outputFile string) error
+}
+
+// RenderOptions holds the settings shared by all renderers.  Zero values are replaced
+// by each renderer's defaults.
+type RenderOptions struct {
+	Background string  // image tiled around the border of each page
+	DPI        float64 // resolution of raster formats
+	Quality    int     // JPEG quality
+	Font       string  // TrueType font for the title and word list (PDF only)
+	GridFont   string  // TrueType font for the grid letters, defaults to Font (PDF only)
+}
+
+// RendererFactory creates a renderer for one output file.
+type RendererFactory func(options RenderOptions) Renderer
+
+var renderers = map[string]RendererFactory{}
+
+// RegisterRenderer makes an output format available to NewRenderer.  Registering a
+// format again replaces the earlier factory.
+func RegisterRenderer(format string, factory RendererFactory) {
+	renderers[strings.ToLower(format)] = factory
+}
+
+// NewRenderer creates a renderer for a registered output format.
+func NewRenderer(format string, options RenderOptions) (Renderer, error) {
+	factory, ok := renderers[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+	return factory(options), nil
+}
+
+// RendererFormats returns the registered output formats in alphabetical order.
+func RendererFormats() []string {
+	formats := make([]string, 0, len(renderers))
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// RenderPuzzle draws the puzzle page, followed by a solution page, and saves them to
+// outputFile.  A puzzle with clues lists them in place of the words, and its solution
+// page lists each clue with its answer.
+func RenderPuzzle(r Renderer, puzzle Puzzle, title string, words []string, columns int, page PageSetup,
+	outputFile string) error {
+	layout, err := NewPageLayout(page, puzzle, len(words), columns)
+	if err != nil {
+		return err
+	}
+
+	if err := r.NewPage(layout); err != nil {
+		return err
+	}
+	r.DrawTitle(title)
+	r.DrawGrid(puzzle)
+	drawWordList(r, puzzle, words)
+
+	if err := r.NewPage(layout); err != nil {
+		return err
+	}
+	r.DrawTitle(title)
+	r.DrawSolution(puzzle)
+	if clues := puzzle.Clues(); len(clues) > 0 {
+		r.DrawClueList(clues, true)
+	}
+
+	return r.Save(outputFile)
+}
+
+// drawWordList draws the word list, or the clues of a puzzle that has them.
+func drawWordList(r Renderer, puzzle Puzzle, words []string) {
+	if clues := puzzle.Clues(); len(clues) > 0 {
+		r.DrawClueList(clues, false)
+		return
+	}
+	r.DrawWordList(words)
+}
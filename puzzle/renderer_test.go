@@ -0,0 +1,114 @@
+package puzzle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordingRenderer records the calls made to it.
+type recordingRenderer struct {
+	calls []string
+}
+
+func (r *recordingRenderer) NewPage(layout PageLayout) error {
+	r.calls = append(r.calls, "NewPage")
+	return nil
+}
+func (r *recordingRenderer) DrawTitle(title string)      { r.calls = append(r.calls, "DrawTitle "+title) }
+func (r *recordingRenderer) DrawGrid(puzzle Puzzle)      { r.calls = append(r.calls, "DrawGrid") }
+func (r *recordingRenderer) DrawWordList(words []string) { r.calls = append(r.calls, "DrawWordList") }
+func (r *recordingRenderer) DrawSolution(puzzle Puzzle)  { r.calls = append(r.calls, "DrawSolution") }
+func (r *recordingRenderer) DrawClueList(clues []Clue, withAnswers bool) {
+	r.calls = append(r.calls, fmt.Sprintf("DrawClueList %v", withAnswers))
+}
+func (r *recordingRenderer) Save(outputFile string) error {
+	r.calls = append(r.calls, "Save "+outputFile)
+	return nil
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	recorder := &recordingRenderer{}
+	RegisterRenderer("Test", func(options RenderOptions) Renderer { return recorder })
+	defer delete(renderers, "test")
+
+	r, err := NewRenderer("TEST", RenderOptions{})
+	if err != nil {
+		t.Fatalf("NewRenderer returned error: %v", err)
+	}
+	puzzle := createPuzzle(3, 3)
+	if err := RenderPuzzle(r, puzzle, "Title", []string{"CAT"}, 0, PageSetup{}, "out.test"); err != nil {
+		t.Fatalf("RenderPuzzle returned error: %v", err)
+	}
+	expected := []string{"NewPage", "DrawTitle Title", "DrawGrid", "DrawWordList",
+		"NewPage", "DrawTitle Title", "DrawSolution", "Save out.test"}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, recorder.calls)
+	}
+
+	formats := strings.Join(RendererFormats(), ",")
+	if !strings.Contains(formats, "pdf") || !strings.Contains(formats, "png") || !strings.Contains(formats, "test") {
+		t.Errorf("Expected pdf, png and test to be registered, got %s", formats)
+	}
+}
+
+func TestRenderPuzzleClues(t *testing.T) {
+	recorder := &recordingRenderer{}
+	puzzle := createPuzzle(5, 5)
+	placeWord(&puzzle, "PARIS", 0, 0, 1, 0, true)
+	puzzle.clues = []Clue{{Text: "Capital of France", Answer: "Paris"}}
+	if err := RenderPuzzle(recorder, puzzle, "Title", []string{"Paris"}, 0, PageSetup{}, "out.test"); err != nil {
+		t.Fatalf("RenderPuzzle returned error: %v", err)
+	}
+	expected := []string{"NewPage", "DrawTitle Title", "DrawGrid", "DrawClueList false",
+		"NewPage", "DrawTitle Title", "DrawSolution", "DrawClueList true", "Save out.test"}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, recorder.calls)
+	}
+}
+
+func TestNewRendererUnknownFormat(t *testing.T) {
+	if _, err := NewRenderer("doc", RenderOptions{}); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestPDFRenderer(t *testing.T) {
+	puzzle := createPuzzle(3, 3)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	r, err := NewRenderer("pdf", RenderOptions{})
+	if err != nil {
+		t.Fatalf("NewRenderer returned error: %v", err)
+	}
+	outputFile := filepath.Join(t.TempDir(), "puzzle.pdf")
+	if err := RenderPuzzle(r, puzzle, "Title", []string{"CAT"}, 0, PageSetup{}, outputFile); err != nil {
+		t.Fatalf("RenderPuzzle returned error: %v", err)
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read PDF output: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "%PDF") {
+		t.Errorf("Expected a PDF file")
+	}
+}
+
+func TestRasterRendererPages(t *testing.T) {
+	puzzle := createPuzzle(3, 3)
+	r, err := NewRenderer("png", RenderOptions{DPI: 20})
+	if err != nil {
+		t.Fatalf("NewRenderer returned error: %v", err)
+	}
+	outputDir := t.TempDir()
+	if err := RenderPuzzle(r, puzzle, "Title", nil, 0, PageSetup{}, filepath.Join(outputDir, "puzzle.png")); err != nil {
+		t.Fatalf("RenderPuzzle returned error: %v", err)
+	}
+	for _, name := range []string{"puzzle.png", "puzzle_2.png"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("Expected page %s to be written: %v", name, err)
+		}
+	}
+}
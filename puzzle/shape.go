@@ -0,0 +1,192 @@
+package puzzle
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maskedCell marks a grid cell that is outside the puzzle shape.  No letters are
+// ever placed in a masked cell and it is rendered blank.
+const maskedCell rune = 0
+
+// Shape is a mask of usable grid cells, indexed as shape[x][y] like the grid.
+type Shape [][]bool
+
+// LoadShape builds a mask of the given width and height.  The name is either one of
+// the built-in shapes (circle, diamond, heart, star) or the path of a mask file.
+// A text mask marks unusable cells with '.' or a space; an image mask (PNG, JPEG or
+// GIF) treats dark, opaque pixels as usable.  Mask files are scaled to fit the grid.
+func LoadShape(name string, width, height int) (Shape, error) {
+	switch strings.ToLower(name) {
+	case "circle":
+		return builtinShape(width, height, func(u, v float64) bool {
+			return u*u+v*v <= 1
+		}), nil
+	case "diamond":
+		return builtinShape(width, height, func(u, v float64) bool {
+			return math.Abs(u)+math.Abs(v) <= 1
+		}), nil
+	case "heart":
+		return builtinShape(width, height, insideHeart), nil
+	case "star":
+		return builtinShape(width, height, insideStar), nil
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return loadImageShape(name, width, height)
+	}
+	return loadTextShape(name, width, height)
+}
+
+// builtinShape evaluates inside at the center of every cell, with u and v scaled to
+// run from -1 to 1 across the grid (v increases downwards).
+func builtinShape(width, height int, inside func(u, v float64) bool) Shape {
+	shape := make(Shape, width)
+	for x := 0; x < width; x++ {
+		shape[x] = make([]bool, height)
+		for y := 0; y < height; y++ {
+			u := (float64(x)+0.5)/float64(width)*2 - 1
+			v := (float64(y)+0.5)/float64(height)*2 - 1
+			shape[x][y] = inside(u, v)
+		}
+	}
+	return shape
+}
+
+// insideHeart uses the heart curve (x^2 + y^2 - 1)^3 - x^2 y^3 = 0, which spans
+// roughly -1.14..1.14 horizontally and -1..1.25 vertically.
+func insideHeart(u, v float64) bool {
+	x := u * 1.2
+	y := 1.25 - (v+1)*1.12
+	a := x*x + y*y - 1
+	return a*a*a-x*x*y*y*y <= 0
+}
+
+// insideStar tests against a five-pointed star with its top point straight up.
+func insideStar(u, v float64) bool {
+	const points = 5
+	const innerRadius = 0.5
+	polygon := make([][2]float64, 0, 2*points)
+	for i := 0; i < 2*points; i++ {
+		radius := 1.0
+		if i%2 == 1 {
+			radius = innerRadius
+		}
+		angle := -math.Pi/2 + float64(i)*math.Pi/points
+		polygon = append(polygon, [2]float64{radius * math.Cos(angle), radius * math.Sin(angle)})
+	}
+
+	// Even-odd ray casting
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		xi, yi := polygon[i][0], polygon[i][1]
+		xj, yj := polygon[j][0], polygon[j][1]
+		if (yi > v) != (yj > v) && u < (xj-xi)*(v-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func loadTextShape(path string, width, height int) (Shape, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rows := make([][]rune, 0)
+	maxLen := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		row := []rune(strings.TrimRight(scanner.Text(), "\r"))
+		if len(row) > maxLen {
+			maxLen = len(row)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || maxLen == 0 {
+		return nil, fmt.Errorf("shape file %s is empty", path)
+	}
+
+	return scaleShape(maxLen, len(rows), width, height, func(x, y int) bool {
+		if x >= len(rows[y]) {
+			return false
+		}
+		return rows[y][x] != '.' && rows[y][x] != ' '
+	}), nil
+}
+
+func loadImageShape(path string, width, height int) (Shape, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load shape image %s: %v", path, err)
+	}
+	bounds := img.Bounds()
+
+	return scaleShape(bounds.Dx(), bounds.Dy(), width, height, func(x, y int) bool {
+		r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+		if a < 0x8000 {
+			return false
+		}
+		luminance := (299*r + 587*g + 114*b) / 1000
+		return luminance < 0x8000
+	}), nil
+}
+
+// scaleShape samples a srcWidth x srcHeight mask at the center of every cell of a
+// width x height grid.
+func scaleShape(srcWidth, srcHeight, width, height int, usable func(x, y int) bool) Shape {
+	shape := make(Shape, width)
+	for x := 0; x < width; x++ {
+		shape[x] = make([]bool, height)
+		srcX := (2*x + 1) * srcWidth / (2 * width)
+		for y := 0; y < height; y++ {
+			srcY := (2*y + 1) * srcHeight / (2 * height)
+			shape[x][y] = usable(srcX, srcY)
+		}
+	}
+	return shape
+}
+
+// applyShape masks every cell of the puzzle that is outside the shape.
+func applyShape(puzzle *Puzzle, shape Shape) {
+	for x := range puzzle.grid {
+		for y := range puzzle.grid[x] {
+			if !shape[x][y] {
+				puzzle.grid[x][y] = maskedCell
+				puzzle.solution[x][y] = maskedCell
+			}
+		}
+	}
+}
+
+func isMaskedCell(grid Grid, x, y int) bool {
+	return grid[x][y] == maskedCell
+}
+
+// cellText returns the text to render for a grid cell.
+func cellText(r rune) string {
+	if r == maskedCell {
+		return " "
+	}
+	return string(r)
+}
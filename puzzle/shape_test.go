@@ -0,0 +1,120 @@
+package puzzle
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestLoadShapeBuiltins(t *testing.T) {
+	for _, name := range []string{"circle", "diamond", "heart", "star"} {
+		shape, err := LoadShape(name, 15, 15)
+		if err != nil {
+			t.Fatalf("LoadShape(%q) returned error: %v", name, err)
+		}
+		if len(shape) != 15 || len(shape[0]) != 15 {
+			t.Fatalf("Expected a 15x15 %s shape, got %dx%d", name, len(shape), len(shape[0]))
+		}
+		if !shape[7][7] {
+			t.Errorf("Expected the center of the %s to be usable", name)
+		}
+		if shape[0][0] || shape[14][14] {
+			t.Errorf("Expected the corners of the %s to be masked", name)
+		}
+	}
+}
+
+func TestLoadShapeTextFile(t *testing.T) {
+	maskContent := []byte("..##..\n.####.\n######\n.####.\n..##..\n")
+	tmpFile, err := os.CreateTemp("", "test_shape_*.txt")
+	if err != nil {
+		t.Fatalf("Failed to create temporary shape file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(maskContent); err != nil {
+		t.Fatalf("Failed to write temporary shape file: %v", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		t.Fatalf("Failed to close temporary shape file: %v", err)
+	}
+
+	shape, err := LoadShape(tmpFile.Name(), 6, 5)
+	if err != nil {
+		t.Fatalf("LoadShape returned error: %v", err)
+	}
+	if shape[0][0] || !shape[2][0] || !shape[0][2] || shape[5][4] {
+		t.Errorf("Text shape was not loaded as expected: %v", shape)
+	}
+
+	// Scaling up to twice the size keeps the same outline
+	shape, err = LoadShape(tmpFile.Name(), 12, 10)
+	if err != nil {
+		t.Fatalf("LoadShape returned error: %v", err)
+	}
+	if shape[1][1] || !shape[5][1] || !shape[0][5] {
+		t.Errorf("Scaled text shape was not loaded as expected: %v", shape)
+	}
+}
+
+func TestLoadShapeImageFile(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			if x < 2 {
+				img.Set(x, y, color.Black)
+			} else {
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+	tmpFile, err := os.CreateTemp("", "test_shape_*.png")
+	if err != nil {
+		t.Fatalf("Failed to create temporary shape image: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if err := png.Encode(tmpFile, img); err != nil {
+		t.Fatalf("Failed to write temporary shape image: %v", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		t.Fatalf("Failed to close temporary shape image: %v", err)
+	}
+
+	shape, err := LoadShape(tmpFile.Name(), 8, 8)
+	if err != nil {
+		t.Fatalf("LoadShape returned error: %v", err)
+	}
+	if !shape[0][0] || !shape[3][7] || shape[4][0] || shape[7][7] {
+		t.Errorf("Image shape was not loaded as expected: %v", shape)
+	}
+}
+
+func TestMaskedCellsAreNotUsed(t *testing.T) {
+	puzzle := createPuzzle(3, 3)
+	shape := Shape{
+		{true, true, true},
+		{true, false, true},
+		{true, true, true},
+	}
+	applyShape(&puzzle, shape)
+
+	if canPlaceWord(puzzle.grid, "ABC", 0, 1, 1, 0, false) {
+		t.Errorf("canPlaceWord placed a word across a masked cell")
+	}
+	if !canPlaceWord(puzzle.grid, "ABC", 0, 0, 1, 0, false) {
+		t.Errorf("canPlaceWord refused a word that avoids the masked cell")
+	}
+
+	if err := fillEmptyCells(puzzle.grid, puzzle.filler, rand.New(rand.NewSource(1))); err != nil {
+		t.Fatalf("fillEmptyCells returned an unexpected error: %v", err)
+	}
+	if !isMaskedCell(puzzle.grid, 1, 1) {
+		t.Errorf("fillEmptyCells filled a masked cell")
+	}
+	if cellText(puzzle.grid[1][1]) != " " {
+		t.Errorf("Expected a masked cell to render blank, got %q", cellText(puzzle.grid[1][1]))
+	}
+}
@@ -0,0 +1,228 @@
+package puzzle
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WordLocation is one occurrence of a word in a grid.  The word starts at (X, Y)
+// and runs in the direction (DX, DY).
+type WordLocation struct {
+	Word string
+	X    int
+	Y    int
+	DX   int
+	DY   int
+}
+
+// Direction returns the name of the direction the word runs in, such as "down-right".
+func (l WordLocation) Direction() string {
+	for name, direction := range directionsByName {
+		if direction == [2]int{l.DX, l.DY} {
+			return name
+		}
+	}
+	return fmt.Sprintf("(%d, %d)", l.DX, l.DY)
+}
+
+func (l WordLocation) String() string {
+	return fmt.Sprintf("(%d, %d) %s", l.X, l.Y, l.Direction())
+}
+
+// WordProblem reports a search word that does not appear exactly once in a grid.
+type WordProblem struct {
+	Word      string
+	Locations []WordLocation
+}
+
+func (p WordProblem) String() string {
+	if len(p.Locations) == 0 {
+		return fmt.Sprintf("%s: not found", p.Word)
+	}
+	locations := make([]string, 0, len(p.Locations))
+	for _, location := range p.Locations {
+		locations = append(locations, location.String())
+	}
+	return fmt.Sprintf("%s: found %d times at %s", p.Word, len(p.Locations), strings.Join(locations, ", "))
+}
+
+// Grid returns the letters of the puzzle, indexed as grid[x][y].
+func (p Puzzle) Grid() Grid {
+	return p.grid
+}
+
+// Words returns the search words placed in the puzzle.
+func (p Puzzle) Words() []string {
+	words := make([]string, 0, len(p.placedWords))
+	for _, placedWord := range p.placedWords {
+		words = append(words, placedWord.word)
+	}
+	return words
+}
+
+// Clues returns the clues shown in place of the word list, in the order they were
+// given, or nil if the puzzle lists its words.
+func (p Puzzle) Clues() []Clue {
+	return p.clues
+}
+
+// Solution returns the grid with only the letters of the search words, indexed as
+// grid[x][y].  Every other cell is blank.
+func (p Puzzle) Solution() Grid {
+	return p.solution
+}
+
+// Locations returns where each search word was placed in the grid.
+func (p Puzzle) Locations() []WordLocation {
+	locations := make([]WordLocation, 0, len(p.placedWords))
+	for _, placedWord := range p.placedWords {
+		locations = append(locations, WordLocation{Word: placedWord.word, X: placedWord.x, Y: placedWord.y,
+			DX: placedWord.dx, DY: placedWord.dy})
+	}
+	return locations
+}
+
+// Solve finds every occurrence of each word in the grid, reading in all eight
+// directions.  Words are uppercased with spaces and punctuation removed but are
+// otherwise matched letter for letter, so a word with accents should be given in the
+// form it is placed in (see PuzzleConfig.GridWords).  The result is keyed by the word
+// as given.
+func Solve(grid Grid, words []string) map[string][]WordLocation {
+	solutions := make(map[string][]WordLocation)
+	for _, word := range words {
+		solutions[word] = findWord(grid, word)
+	}
+	return solutions
+}
+
+// Verify checks that each word appears exactly once in the grid and returns a
+// problem for every word that is missing or appears more than once.
+func Verify(grid Grid, words []string) []WordProblem {
+	problems := make([]WordProblem, 0)
+	solutions := Solve(grid, words)
+	for _, word := range words {
+		if len(solutions[word]) != 1 {
+			problems = append(problems, WordProblem{Word: word, Locations: solutions[word]})
+		}
+	}
+	return problems
+}
+
+func findWord(grid Grid, word string) []WordLocation {
+	runes := []rune(normalizeWord(word))
+	locations := make([]WordLocation, 0)
+	if len(runes) == 0 {
+		return locations
+	}
+
+	// A palindrome (or a single letter) reads the same in opposite directions, so
+	// each set of cells is only counted once.
+	seen := make(map[[4]int]bool)
+	for x := range grid {
+		for y := range grid[x] {
+			if grid[x][y] != runes[0] {
+				continue
+			}
+			for _, direction := range allDirections {
+				dx, dy := direction[0], direction[1]
+				if !matchesAt(grid, runes, x, y, dx, dy) {
+					continue
+				}
+				endX, endY := x+(len(runes)-1)*dx, y+(len(runes)-1)*dy
+				key := [4]int{x, y, endX, endY}
+				if endX < x || (endX == x && endY < y) {
+					key = [4]int{endX, endY, x, y}
+				}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				locations = append(locations, WordLocation{Word: word, X: x, Y: y, DX: dx, DY: dy})
+			}
+		}
+	}
+	return locations
+}
+
+func matchesAt(grid Grid, runes []rune, x, y, dx, dy int) bool {
+	for i, r := range runes {
+		newX := x + i*dx
+		newY := y + i*dy
+		if !inBounds(grid, newX, newY) || grid[newX][newY] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadGridFromFile reads a puzzle grid from a text file with one row per line, such
+// as the files written by SavePuzzleToFile.  Letters may be separated by single
+// spaces.  Reading stops at the first empty line, so a solution or seed written after
+// the grid is ignored.  Blank cells are treated as outside the puzzle shape.
+func LoadGridFromFile(filename string) (Grid, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rows := make([][]rune, 0)
+	width := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			if len(rows) == 0 {
+				continue
+			}
+			break
+		}
+		row := parseGridRow([]rune(line))
+		if len(row) > width {
+			width = len(row)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no grid found in " + filename)
+	}
+
+	grid := createEmptyGrid(width, len(rows))
+	for y, row := range rows {
+		for x := 0; x < width; x++ {
+			if x < len(row) && row[x] != ' ' {
+				grid[x][y] = row[x]
+			} else {
+				grid[x][y] = maskedCell
+			}
+		}
+	}
+	return grid, nil
+}
+
+// parseGridRow splits a line into cells.  When every other character is a space the
+// line is in the "A B C " format and the spaces are separators.
+func parseGridRow(line []rune) []rune {
+	spaced := len(line) > 1
+	for i := 1; i < len(line); i += 2 {
+		if line[i] != ' ' {
+			spaced = false
+			break
+		}
+	}
+	if !spaced {
+		return line
+	}
+
+	row := make([]rune, 0, (len(line)+1)/2)
+	for i := 0; i < len(line); i += 2 {
+		row = append(row, line[i])
+	}
+	return row
+}
@@ -0,0 +1,136 @@
+package puzzle
+
+import (
+	"os"
+	"testing"
+)
+
+// gridFromRows builds a grid (indexed as grid[x][y]) from rows of text.
+func gridFromRows(rows ...string) Grid {
+	grid := createEmptyGrid(len([]rune(rows[0])), len(rows))
+	for y, row := range rows {
+		for x, r := range []rune(row) {
+			grid[x][y] = r
+		}
+	}
+	return grid
+}
+
+func TestSolve(t *testing.T) {
+	grid := gridFromRows(
+		"CATX",
+		"XAXX",
+		"TACX",
+		"NOON",
+	)
+
+	solutions := Solve(grid, []string{"cat", "NOON", "A", "DOG"})
+
+	// CAT reads right on the first row and left on the third row
+	if len(solutions["cat"]) != 2 {
+		t.Errorf("Expected CAT to be found 2 times, got %v", solutions["cat"])
+	}
+	for _, location := range solutions["cat"] {
+		if location.Word != "cat" {
+			t.Errorf("Expected location word to be %q, got %q", "cat", location.Word)
+		}
+	}
+	if len(solutions["NOON"]) != 1 {
+		t.Errorf("Expected the palindrome NOON to be found once, got %v", solutions["NOON"])
+	}
+	if len(solutions["A"]) != 3 {
+		t.Errorf("Expected A to be found 3 times, got %v", solutions["A"])
+	}
+	if len(solutions["DOG"]) != 0 {
+		t.Errorf("Expected DOG not to be found, got %v", solutions["DOG"])
+	}
+}
+
+func TestVerify(t *testing.T) {
+	grid := gridFromRows(
+		"CATX",
+		"DOGX",
+		"XXXX",
+		"TACX",
+	)
+
+	problems := Verify(grid, []string{"CAT", "DOG", "BIRD"})
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %v", problems)
+	}
+	if problems[0].Word != "CAT" || len(problems[0].Locations) != 2 {
+		t.Errorf("Expected CAT to be reported twice, got %v", problems[0])
+	}
+	if problems[1].Word != "BIRD" || len(problems[1].Locations) != 0 {
+		t.Errorf("Expected BIRD to be reported missing, got %v", problems[1])
+	}
+	if problems[1].String() != "BIRD: not found" {
+		t.Errorf("Unexpected problem description %q", problems[1].String())
+	}
+}
+
+func TestLoadGridFromFile(t *testing.T) {
+	puzzle := createPuzzle(5, 3)
+	applyShape(&puzzle, Shape{
+		{false, true, true},
+		{true, true, true},
+		{true, true, true},
+		{true, true, true},
+		{true, true, true},
+	})
+	placeWord(&puzzle, "HORSE", 0, 1, 1, 0, true)
+	for x := range puzzle.grid {
+		for y := range puzzle.grid[x] {
+			if isEmptyCell(puzzle.grid, x, y) {
+				puzzle.grid[x][y] = 'Q'
+			}
+		}
+	}
+
+	tmpFile, err := os.CreateTemp("", "test_grid_*.txt")
+	if err != nil {
+		t.Fatalf("Failed to create temporary grid file: %v", err)
+	}
+	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	if err := SavePuzzleToFile(puzzle, tmpFile.Name(), true); err != nil {
+		t.Fatalf("SavePuzzleToFile returned error: %v", err)
+	}
+	grid, err := LoadGridFromFile(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("LoadGridFromFile returned error: %v", err)
+	}
+
+	if len(grid) != 5 || len(grid[0]) != 3 {
+		t.Fatalf("Expected a 5x3 grid, got %dx%d", len(grid), len(grid[0]))
+	}
+	for x := range grid {
+		for y := range grid[x] {
+			if grid[x][y] != puzzle.grid[x][y] {
+				t.Errorf("Expected %q at (%d,%d), got %q", puzzle.grid[x][y], x, y, grid[x][y])
+			}
+		}
+	}
+	if problems := Verify(grid, puzzle.Words()); len(problems) != 0 {
+		t.Errorf("Expected no problems, got %v", problems)
+	}
+}
+
+func TestParseGridRow(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected string
+	}{
+		{"A B C ", "ABC"},
+		{"ABC", "ABC"},
+		{"  B C", " BC"},
+	}
+
+	for _, tc := range testCases {
+		row := string(parseGridRow([]rune(tc.line)))
+		if row != tc.expected {
+			t.Errorf("Expected parseGridRow(%q) to be %q, got %q", tc.line, tc.expected, row)
+		}
+	}
+}
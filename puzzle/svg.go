@@ -0,0 +1,89 @@
+package puzzle
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"os"
+)
+
+// SVGOptions controls the appearance of SVG output.  Zero values are replaced by the
+// defaults: Courier, 40px cells, black letters, grey strike lines and a white background.
+type SVGOptions struct {
+	FontFamily      string  `yaml:"font"`
+	CellSize        float64 `yaml:"cell_size"`
+	TextColor       string  `yaml:"text_color"`
+	LineColor       string  `yaml:"line_color"`
+	BackgroundColor string  `yaml:"background_color"`
+}
+
+func (o SVGOptions) withDefaults() SVGOptions {
+	if o.FontFamily == "" {
+		o.FontFamily = "Courier, monospace"
+	}
+	if o.CellSize <= 0 {
+		o.CellSize = 40
+	}
+	if o.TextColor == "" {
+		o.TextColor = "#000000"
+	}
+	if o.LineColor == "" {
+		o.LineColor = "#c0c0c0"
+	}
+	if o.BackgroundColor == "" {
+		o.BackgroundColor = "#ffffff"
+	}
+	return o
+}
+
+// GenerateSVG writes the puzzle grid as an SVG image.  When isSolution is true only the
+// letters of the search words are drawn, with a line through each placed word.
+func GenerateSVG(puzzle Puzzle, outputFile string, isSolution bool, options SVGOptions) error {
+	options = options.withDefaults()
+	cellSize := options.CellSize
+	width := float64(puzzle.Width()) * cellSize
+	height := float64(puzzle.Height()) * cellSize
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	fmt.Fprintf(writer, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(writer, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%g\" height=\"%g\" viewBox=\"0 0 %g %g\">\n",
+		width, height, width, height)
+	fmt.Fprintf(writer, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", html.EscapeString(options.BackgroundColor))
+
+	gr := puzzle.grid
+	if isSolution {
+		gr = puzzle.solution
+		fmt.Fprintf(writer, "<g stroke=\"%s\" stroke-width=\"%g\" stroke-linecap=\"round\">\n",
+			html.EscapeString(options.LineColor), cellSize/10)
+		for _, word := range puzzle.placedWords {
+			startX := float64(word.x)*cellSize + (cellSize / 2)
+			startY := float64(word.y)*cellSize + (cellSize / 2)
+			endX := startX + float64(word.dx*(len([]rune(word.word))-1))*cellSize
+			endY := startY + float64(word.dy*(len([]rune(word.word))-1))*cellSize
+			fmt.Fprintf(writer, "<line x1=\"%g\" y1=\"%g\" x2=\"%g\" y2=\"%g\"/>\n", startX, startY, endX, endY)
+		}
+		fmt.Fprintf(writer, "</g>\n")
+	}
+
+	fmt.Fprintf(writer, "<g font-family=\"%s\" font-size=\"%g\" font-weight=\"bold\" fill=\"%s\" "+
+		"text-anchor=\"middle\" dominant-baseline=\"central\">\n",
+		html.EscapeString(options.FontFamily), cellSize*0.6, html.EscapeString(options.TextColor))
+	for y := 0; y < puzzle.Height(); y++ {
+		for x := 0; x < puzzle.Width(); x++ {
+			if gr[x][y] == ' ' || isMaskedCell(gr, x, y) {
+				continue
+			}
+			fmt.Fprintf(writer, "<text x=\"%g\" y=\"%g\">%s</text>\n",
+				(float64(x)+0.5)*cellSize, (float64(y)+0.5)*cellSize, html.EscapeString(string(gr[x][y])))
+		}
+	}
+	fmt.Fprintf(writer, "</g>\n</svg>\n")
+
+	return writer.Flush()
+}
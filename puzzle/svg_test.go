@@ -0,0 +1,57 @@
+package puzzle
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSVG(t *testing.T) {
+	puzzle := createPuzzle(3, 2)
+	placeWord(&puzzle, "CAT", 0, 0, 1, 0, true)
+	for x, r := range "D<G" {
+		puzzle.grid[x][1] = r
+	}
+
+	outputDir := t.TempDir()
+	puzzleFile := filepath.Join(outputDir, "puzzle.svg")
+	options := SVGOptions{CellSize: 20, LineColor: "red"}
+	if err := GenerateSVG(puzzle, puzzleFile, false, options); err != nil {
+		t.Fatalf("GenerateSVG returned error: %v", err)
+	}
+	data, err := os.ReadFile(puzzleFile)
+	if err != nil {
+		t.Fatalf("Failed to read SVG output: %v", err)
+	}
+	svg := string(data)
+	if err := xml.Unmarshal(data, new(interface{})); err != nil {
+		t.Errorf("SVG output is not valid XML: %v", err)
+	}
+	if !strings.Contains(svg, `width="60" height="40"`) {
+		t.Errorf("Expected a 60x40 SVG image")
+	}
+	if !strings.Contains(svg, `<text x="30" y="30">&lt;</text>`) {
+		t.Errorf("Expected the escaped grid letter in the SVG output")
+	}
+	if strings.Contains(svg, "<line") {
+		t.Errorf("Expected no strike lines in the puzzle SVG")
+	}
+
+	solutionFile := filepath.Join(outputDir, "solution.svg")
+	if err := GenerateSVG(puzzle, solutionFile, true, options); err != nil {
+		t.Fatalf("GenerateSVG returned error: %v", err)
+	}
+	data, err = os.ReadFile(solutionFile)
+	if err != nil {
+		t.Fatalf("Failed to read SVG output: %v", err)
+	}
+	svg = string(data)
+	if !strings.Contains(svg, `<line x1="10" y1="10" x2="50" y2="10"/>`) || !strings.Contains(svg, `stroke="red"`) {
+		t.Errorf("Expected a red strike line through CAT in the solution SVG")
+	}
+	if strings.Contains(svg, ">D</text>") {
+		t.Errorf("Expected only search word letters in the solution SVG")
+	}
+}
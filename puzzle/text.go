@@ -9,9 +9,9 @@ import (
 // PrintPuzzle prints the grid of the puzzle one row at a time. It takes in a parameter
 // puzzle of type Puzzle.
 func PrintPuzzle(puzzle Puzzle) {
-	for y := 0; y < len(puzzle.grid); y++ {
-		for x := 0; x < len(puzzle.grid); x++ {
-			fmt.Printf("%c ", puzzle.grid[x][y])
+	for y := 0; y < puzzle.Height(); y++ {
+		for x := 0; x < puzzle.Width(); x++ {
+			fmt.Printf("%s ", cellText(puzzle.grid[x][y]))
 		}
 		fmt.Println()
 	}
@@ -26,9 +26,9 @@ func SavePuzzleToFile(puzzle Puzzle, filename string, includeSolution bool) erro
 	defer file.Close()
 
 	writer := bufio.NewWriter(file)
-	for y := 0; y < len(puzzle.grid); y++ {
-		for x := 0; x < len(puzzle.grid); x++ {
-			_, err = writer.WriteString(fmt.Sprintf("%c ", puzzle.grid[x][y]))
+	for y := 0; y < puzzle.Height(); y++ {
+		for x := 0; x < puzzle.Width(); x++ {
+			_, err = writer.WriteString(fmt.Sprintf("%s ", cellText(puzzle.grid[x][y])))
 			if err != nil {
 				return err
 			}
@@ -44,9 +44,9 @@ func SavePuzzleToFile(puzzle Puzzle, filename string, includeSolution bool) erro
 		if err != nil {
 			return err
 		}
-		for y := 0; y < len(puzzle.grid); y++ {
-			for x := 0; x < len(puzzle.grid); x++ {
-				_, err = writer.WriteString(fmt.Sprintf("%c ", puzzle.solution[x][y]))
+		for y := 0; y < puzzle.Height(); y++ {
+			for x := 0; x < puzzle.Width(); x++ {
+				_, err = writer.WriteString(fmt.Sprintf("%s ", cellText(puzzle.solution[x][y])))
 				if err != nil {
 					return err
 				}
@@ -57,5 +57,16 @@ func SavePuzzleToFile(puzzle Puzzle, filename string, includeSolution bool) erro
 			}
 		}
 	}
+	if includeSolution && puzzle.hiddenMessage != "" {
+		_, err = writer.WriteString(fmt.Sprintf("\nHidden message: %s\n", puzzle.hiddenMessage))
+		if err != nil {
+			return err
+		}
+	}
+	// Write the seed so the puzzle can be regenerated
+	_, err = writer.WriteString(fmt.Sprintf("\nSeed: %d\n", puzzle.seed))
+	if err != nil {
+		return err
+	}
 	return writer.Flush()
 }
@@ -1,27 +1,81 @@
 package puzzle
 
 import (
+	"fmt"
 	"math/rand"
+	"strings"
 )
 
-func randomDirection() (int, int) {
-	directions := [][2]int{
-		{1, 0},   // Right
-		{0, 1},   // Down
-		{1, 1},   // Diagonal down-right
-		{-1, 0},  // Left
-		{0, -1},  // Up
-		{-1, -1}, // Diagonal up-left
-		{1, -1},  // Diagonal up-right
-		{-1, 1},  // Diagonal down-left
+// allDirections lists every direction (dx, dy) a word can run in the grid.
+var allDirections = [][2]int{
+	{1, 0},   // Right
+	{0, 1},   // Down
+	{1, 1},   // Diagonal down-right
+	{-1, 0},  // Left
+	{0, -1},  // Up
+	{-1, -1}, // Diagonal up-left
+	{1, -1},  // Diagonal up-right
+	{-1, 1},  // Diagonal down-left
+}
+
+// directionsByName maps the direction names allowed in the config to (dx, dy).
+var directionsByName = map[string][2]int{
+	"right":      {1, 0},
+	"down":       {0, 1},
+	"down-right": {1, 1},
+	"left":       {-1, 0},
+	"up":         {0, -1},
+	"up-left":    {-1, -1},
+	"up-right":   {1, -1},
+	"down-left":  {-1, 1},
+}
+
+func randomDirection(rng *rand.Rand) (int, int) {
+	index := rng.Intn(len(allDirections))
+	return allDirections[index][0], allDirections[index][1]
+}
+
+// puzzleDirections returns the directions words may be placed in.  Named directions
+// from the config are used when given, otherwise the default for the difficulty.
+func puzzleDirections(names []string, difficulty int) ([][2]int, error) {
+	if len(names) == 0 {
+		return defaultDirections(difficulty), nil
 	}
 
-	index := rand.Intn(len(directions))
-	return directions[index][0], directions[index][1]
+	directions := make([][2]int, 0, len(names))
+	seen := make(map[[2]int]bool)
+	for _, name := range names {
+		direction, ok := directionsByName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("invalid direction '%s'", name)
+		}
+		if !seen[direction] {
+			seen[direction] = true
+			directions = append(directions, direction)
+		}
+	}
+	return directions, nil
+}
+
+// defaultDirections returns the directions used for a difficulty.  Easy puzzles
+// only read left to right and top to bottom; backwards words start at difficulty 7
+// and all eight directions are used at difficulty 9.
+func defaultDirections(difficulty int) [][2]int {
+	switch {
+	case difficulty <= 2:
+		return [][2]int{{1, 0}, {0, 1}}
+	case difficulty <= 4:
+		return [][2]int{{1, 0}, {0, 1}, {1, 1}}
+	case difficulty <= 6:
+		return [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+	case difficulty <= 8:
+		return [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}, {-1, 0}, {0, -1}}
+	}
+	return allDirections
 }
 
 func inBounds(grid Grid, x, y int) bool {
-	return x >= 0 && x < len(grid) && y >= 0 && y < len(grid)
+	return x >= 0 && x < len(grid) && y >= 0 && y < len(grid[x])
 }
 
 func isEmptyCell(grid Grid, x, y int) bool {
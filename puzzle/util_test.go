@@ -1,12 +1,14 @@
 package puzzle
 
 import (
+	"math/rand"
 	"testing"
 )
 
 func TestRandomDirection(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
 	for i := 0; i < 100; i++ {
-		dx, dy := randomDirection()
+		dx, dy := randomDirection(rng)
 		if dx < -1 || dx > 1 || dy < -1 || dy > 1 {
 			t.Errorf("Invalid direction (%d, %d)", dx, dy)
 		}
@@ -38,6 +40,27 @@ func TestInBounds(t *testing.T) {
 	}
 }
 
+func TestInBoundsRectangular(t *testing.T) {
+	grid := createEmptyGrid(6, 3)
+
+	testCases := []struct {
+		x, y  int
+		valid bool
+	}{
+		{5, 0, true},
+		{5, 2, true},
+		{6, 0, false},
+		{0, 3, false},
+		{2, 5, false},
+	}
+
+	for _, tc := range testCases {
+		if inBounds(grid, tc.x, tc.y) != tc.valid {
+			t.Errorf("Expected inBounds to return %v for x=%d, y=%d", tc.valid, tc.x, tc.y)
+		}
+	}
+}
+
 func TestIsEmptyCell(t *testing.T) {
 	grid := Grid{
 		{'A', 'B', 'C', 'D', 'E'},
@@ -63,3 +86,44 @@ func TestIsEmptyCell(t *testing.T) {
 		}
 	}
 }
+
+func TestPuzzleDirections(t *testing.T) {
+	directions, err := puzzleDirections([]string{"right", "Down", "down-right", "right"}, 9)
+	if err != nil {
+		t.Fatalf("puzzleDirections returned error: %v", err)
+	}
+	expected := [][2]int{{1, 0}, {0, 1}, {1, 1}}
+	if len(directions) != len(expected) {
+		t.Fatalf("Expected %d directions, got %d", len(expected), len(directions))
+	}
+	for i, direction := range directions {
+		if direction != expected[i] {
+			t.Errorf("Expected direction %d to be %v, got %v", i, expected[i], direction)
+		}
+	}
+
+	if _, err := puzzleDirections([]string{"diagonal"}, 1); err == nil {
+		t.Errorf("Expected an error for an invalid direction name")
+	}
+}
+
+func TestDefaultDirections(t *testing.T) {
+	for difficulty := 1; difficulty <= 9; difficulty++ {
+		directions := defaultDirections(difficulty)
+		hasBackwards := false
+		for _, direction := range directions {
+			if direction[0] < 0 || (direction[0] == 0 && direction[1] < 0) {
+				hasBackwards = true
+			}
+		}
+		if difficulty < 7 && hasBackwards {
+			t.Errorf("Expected no backwards directions at difficulty %d", difficulty)
+		}
+		if difficulty >= 7 && !hasBackwards {
+			t.Errorf("Expected backwards directions at difficulty %d", difficulty)
+		}
+	}
+	if len(defaultDirections(1)) != 2 || len(defaultDirections(9)) != 8 {
+		t.Errorf("Expected 2 directions at difficulty 1 and 8 at difficulty 9")
+	}
+}
@@ -0,0 +1,346 @@
+package puzzle
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxPuzzleSize is the largest width or height of a puzzle grid.
+const MaxPuzzleSize = 1024
+
+// ConfigError is a problem with a config file, at the line and column of the YAML
+// it is about.
+type ConfigError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// ConfigErrors holds every problem found in a config file, in the order they appear.
+type ConfigErrors []*ConfigError
+
+func (e ConfigErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e *ConfigErrors) add(node *yaml.Node, format string, args ...interface{}) {
+	*e = append(*e, &ConfigError{Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, args...)})
+}
+
+// sort puts the errors in the order of the places they are about.
+func (e ConfigErrors) sort() {
+	sort.SliceStable(e, func(i, j int) bool {
+		if e[i].Line != e[j].Line {
+			return e[i].Line < e[j].Line
+		}
+		return e[i].Column < e[j].Column
+	})
+}
+
+var unmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// checkFields walks the YAML node that decodes into a value of type t, and reports
+// keys that match no field (with the closest field name, since most are typos) and
+// values of the wrong kind, such as text where a number belongs.
+func checkFields(node *yaml.Node, t reflect.Type, name string, errs *ConfigErrors) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node.ShortTag() == "!!null" {
+		return
+	}
+	// Types with their own decoding, such as WordEntry, also accept a plain value
+	if reflect.PtrTo(t).Implements(unmarshalerType) && node.Kind == yaml.ScalarNode {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			errs.add(node, "%s must be a mapping", name)
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				errs.add(key, "unknown field %q%s", key.Value, suggestField(key.Value, fields))
+				continue
+			}
+			checkFields(value, field, key.Value, errs)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			errs.add(node, "%s must be a list", name)
+			return
+		}
+		for _, item := range node.Content {
+			checkFields(item, t.Elem(), name, errs)
+		}
+	case reflect.Int, reflect.Int64:
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!int" {
+			errs.add(node, "%s must be a whole number, got %s", name, nodeText(node))
+		}
+	case reflect.Float64:
+		if node.Kind != yaml.ScalarNode || (node.ShortTag() != "!!int" && node.ShortTag() != "!!float") {
+			errs.add(node, "%s must be a number, got %s", name, nodeText(node))
+		}
+	case reflect.Bool:
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!bool" {
+			errs.add(node, "%s must be true or false, got %s", name, nodeText(node))
+		}
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			errs.add(node, "%s must be text", name)
+		}
+	}
+}
+
+// yamlFields maps the YAML keys of a struct type to the types of their fields,
+// including the fields of inlined structs.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("yaml"), ",")
+		key := tag[0]
+		if key == "-" {
+			continue
+		}
+		if len(tag) > 1 && tag[1] == "inline" {
+			for name, fieldType := range yamlFields(field.Type) {
+				fields[name] = fieldType
+			}
+			continue
+		}
+		if key == "" {
+			key = strings.ToLower(field.Name)
+		}
+		fields[key] = field.Type
+	}
+	return fields
+}
+
+// suggestField returns a hint naming the field closest to an unknown key, or "" if
+// none is close.
+func suggestField(key string, fields map[string]reflect.Type) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if d := editDistance(key, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// editDistance returns the number of single character insertions, deletions and
+// substitutions that turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// nodeText describes a node's value for an error message.
+func nodeText(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	return fmt.Sprintf("%q", node.Value)
+}
+
+// mappingValue returns the value of key in a mapping node, or nil if the key is not
+// there.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// readWordSources adds the words of the words file and includes to the inline words,
+// leaving out any word already in the list.  Relative file names are taken to be in
+// dir, the directory of the config file.  The problems reading each file are reported
+// at the place in node (the config's mapping) it was named.
+func (c *PuzzleConfig) readWordSources(node *yaml.Node, dir string) ConfigErrors {
+	var errs ConfigErrors
+	var sources []WordSource
+	var sourceNodes []*yaml.Node
+	if c.WordsFile != "" {
+		sources = append(sources, WordSource{File: c.WordsFile})
+		sourceNodes = append(sourceNodes, mappingValue(node, "words_file"))
+	}
+	if includes := mappingValue(node, "include"); includes != nil {
+		sources = append(sources, c.Include...)
+		sourceNodes = append(sourceNodes, includes.Content...)
+	}
+
+	entries := c.Words
+	for i, source := range sources {
+		read, err := source.Read(dir)
+		if err != nil {
+			errs.add(sourceNodes[i], "%v", err)
+			continue
+		}
+		for _, entry := range read {
+			if entry.node == nil {
+				entry.node = sourceNodes[i]
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	c.Words = entries
+	alphabet, _ := gridAlphabet(c.Alphabet, c.Language)
+	seen := make(map[string]bool)
+	words := make([]WordEntry, 0, len(entries))
+	for _, entry := range entries {
+		key := entry.GridWord(alphabet)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		words = append(words, entry)
+	}
+	c.Words = words
+	return errs
+}
+
+// checkValues reports config values that are out of range or unknown, at the place
+// in node (the config's mapping) they were given.
+func (c *PuzzleConfig) checkValues(node *yaml.Node) ConfigErrors {
+	var errs ConfigErrors
+	at := func(key string) *yaml.Node {
+		if value := mappingValue(node, key); value != nil {
+			return value
+		}
+		return node
+	}
+
+	if c.Difficulty < 1 || c.Difficulty > 9 {
+		if mappingValue(node, "difficulty") == nil {
+			errs.add(node, "difficulty is required (1-9)")
+		} else {
+			errs.add(at("difficulty"), "difficulty must be from 1 to 9, got %d", c.Difficulty)
+		}
+	}
+	for _, dimension := range []struct {
+		key   string
+		value int
+	}{{"size", c.Size}, {"width", c.Width}, {"height", c.Height}} {
+		// 0 leaves the size to be picked automatically, as when it is left out
+		if value := mappingValue(node, dimension.key); value != nil &&
+			(dimension.value < 0 || dimension.value > MaxPuzzleSize) {
+			errs.add(value, "%s must be from 0 (automatic) to %d, got %d", dimension.key, MaxPuzzleSize,
+				dimension.value)
+		}
+	}
+	if value := mappingValue(node, "columns"); value != nil && c.Columns < 1 {
+		errs.add(value, "columns must be at least 1, got %d", c.Columns)
+	}
+	if value := mappingValue(node, "max_attempts"); value != nil && c.MaxAttempts < 1 {
+		errs.add(value, "max_attempts must be at least 1, got %d", c.MaxAttempts)
+	}
+
+	if value := mappingValue(node, "directions"); value != nil {
+		for i, direction := range c.Directions {
+			if _, err := puzzleDirections([]string{direction}, c.Difficulty); err != nil {
+				errs.add(value.Content[i], "%v", err)
+			}
+		}
+	}
+	alphabet, err := gridAlphabet(c.Alphabet, c.Language)
+	if err != nil {
+		key := "language"
+		if c.Alphabet != "" {
+			key = "alphabet"
+		}
+		errs.add(at(key), "%v", err)
+	}
+	if c.Fill != "" {
+		if _, ok := fillers[strings.ToLower(c.Fill)]; !ok {
+			errs.add(at("fill"), "unknown fill %q (expected one of %s)", c.Fill, strings.Join(fillerNames(), ", "))
+		}
+	}
+	if _, err := fillCharacter(c.FillCharacter); err != nil {
+		errs.add(at("fill_character"), "%v", err)
+	}
+
+	for _, entry := range c.Words {
+		if word := entry.GridWord(alphabet); !isValidWord(word) {
+			entryNode := entry.node
+			if entryNode == nil {
+				entryNode = at("words")
+			}
+			errs.add(entryNode, "word %q has no letters to place, or characters that are not letters",
+				entry.DisplayText())
+		}
+	}
+	if _, err := wordClues(c.Words); err != nil {
+		errs.add(at("words"), "%v", err)
+	}
+
+	if _, _, err := (PageSetup{Size: c.PageSetup.Size}).PageSize(); err != nil {
+		errs.add(at("page_size"), "%v", err)
+	} else if _, _, err := (PageSetup{Orientation: c.Orientation}).PageSize(); err != nil {
+		errs.add(at("orientation"), "%v", err)
+	} else if err := c.PageSetup.validate(); err != nil {
+		errs.add(at("margins"), "%v", err)
+	}
+	errs.sort()
+	return errs
+}
@@ -0,0 +1,125 @@
+package puzzle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// parseConfigErrors parses a config and returns its errors as strings.
+func parseConfigErrors(t *testing.T, content string) []string {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	_, err := ParseConfig(configFile)
+	if err == nil {
+		return nil
+	}
+	var configErrors ConfigErrors
+	if !errors.As(err, &configErrors) {
+		t.Fatalf("Expected ConfigErrors, got %v", err)
+	}
+	messages := make([]string, 0, len(configErrors))
+	for _, configError := range configErrors {
+		messages = append(messages, configError.Error())
+	}
+	return messages
+}
+
+func TestParseConfigUnknownFields(t *testing.T) {
+	errs := parseConfigErrors(t, `title: Typos
+difficultly: 3
+colums: 4
+svg:
+  cell_sise: 10
+margins:
+  topp: 10
+words: [cat]
+`)
+	expected := []string{
+		`line 2, column 1: unknown field "difficultly" (did you mean "difficulty"?)`,
+		`line 3, column 1: unknown field "colums" (did you mean "columns"?)`,
+		`line 5, column 3: unknown field "cell_sise" (did you mean "cell_size"?)`,
+		`line 7, column 3: unknown field "topp" (did you mean "top"?)`,
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected errors %q, got %q", expected, errs)
+	}
+}
+
+func TestParseConfigTypes(t *testing.T) {
+	errs := parseConfigErrors(t, `difficulty: hard
+allow_duplicates: maybe
+margins: wide
+words: cat
+`)
+	expected := []string{
+		`line 1, column 13: difficulty must be a whole number, got "hard"`,
+		`line 2, column 19: allow_duplicates must be true or false, got "maybe"`,
+		`line 4, column 8: words must be a list`,
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected errors %q, got %q", expected, errs)
+	}
+
+	errs = parseConfigErrors(t, "difficulty: 3\nmargins: wide\nwords: [cat]\n")
+	expected = []string{`line 2, column 10: margins must be a number or a mapping, got "wide"`}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected errors %q, got %q", expected, errs)
+	}
+}
+
+func TestParseConfigRanges(t *testing.T) {
+	errs := parseConfigErrors(t, `difficulty: 12
+size: -3
+columns: 0
+directions: [right, sideways]
+language: xx
+words:
+  - cat
+  - "!!"
+`)
+	expected := []string{
+		`line 1, column 13: difficulty must be from 1 to 9, got 12`,
+		`line 2, column 7: size must be from 0 (automatic) to 1024, got -3`,
+		`line 3, column 10: columns must be at least 1, got 0`,
+		`line 4, column 21: invalid direction 'sideways'`,
+		`line 5, column 11: unknown language "xx" (expected one of da, de, el, en, es, fi, fr, it, nl, no, pl, pt, ru, sv, tr, uk)`,
+		`line 8, column 5: word "!!" has no letters to place, or characters that are not letters`,
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected errors %q, got %q", expected, errs)
+	}
+
+	if errs := parseConfigErrors(t, "difficulty: 3\nsize: 0\nwidth: 0\nwords: [cat]\n"); errs != nil {
+		t.Errorf("Expected a size of 0 to be picked automatically, got %q", errs)
+	}
+
+	errs = parseConfigErrors(t, "title: No difficulty\nwords: [cat]\n")
+	expected = []string{`line 1, column 1: difficulty is required (1-9)`}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected errors %q, got %q", expected, errs)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		distance int
+	}{
+		{"columns", "columns", 0},
+		{"colums", "columns", 1},
+		{"difficultly", "difficulty", 1},
+		{"size", "seed", 3},
+		{"", "abc", 3},
+	}
+	for _, tc := range testCases {
+		if d := editDistance(tc.a, tc.b); d != tc.distance {
+			t.Errorf("Expected editDistance(%q, %q) to be %d, got %d", tc.a, tc.b, tc.distance, d)
+		}
+	}
+}
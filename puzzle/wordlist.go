@@ -2,12 +2,127 @@ package puzzle
 
 import (
 	"bufio"
+	"encoding/csv"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"unicode"
+
+	"gopkg.in/yaml.v3"
 )
 
+// WordEntry is one search word.  Display is how the word is shown in the word list
+// and Grid is the letters placed in the grid.  In YAML an entry is either a plain
+// string, used for both, or a mapping with display and grid keys.  A missing grid form
+// is made from the display form by normalizeGridWord.
+//
+// An entry with a clue is written as a mapping with word and clue keys; the word list
+// then shows the clue and the word is the answer.
+type WordEntry struct {
+	Display string `yaml:"display"`
+	Grid    string `yaml:"grid"`
+	Word    string `yaml:"word"`
+	Clue    string `yaml:"clue"`
+
+	// Where the entry was given in the config, for error messages
+	node *yaml.Node
+}
+
+func (e *WordEntry) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*e = WordEntry{Display: value.Value, node: value}
+		return nil
+	}
+	// Decode through a type without this method to use the plain struct decoding
+	type plain WordEntry
+	var entry plain
+	if err := value.Decode(&entry); err != nil {
+		return err
+	}
+	if entry.Display == "" && entry.Grid == "" && entry.Word == "" {
+		return &ConfigError{Line: value.Line, Column: value.Column,
+			Message: "word entry needs a word, display or grid form"}
+	}
+	*e = WordEntry(entry)
+	e.node = value
+	return nil
+}
+
+// Clue is a clue shown in place of a search word, and the word that answers it.
+type Clue struct {
+	Text   string `json:"clue"`
+	Answer string `json:"answer"`
+}
+
+// wordClues returns the clue of each entry, or nil when no entry has a clue.  Either
+// every entry has a clue or none does.
+func wordClues(entries []WordEntry) ([]Clue, error) {
+	clues := make([]Clue, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Clue == "" {
+			continue
+		}
+		clues = append(clues, Clue{Text: entry.Clue, Answer: entry.DisplayText()})
+	}
+	if len(clues) == 0 {
+		return nil, nil
+	}
+	for _, entry := range entries {
+		if entry.Clue == "" {
+			return nil, fmt.Errorf("word %q has no clue (give every word a clue, or none)", entry.DisplayText())
+		}
+	}
+	return clues, nil
+}
+
+// clueText returns the text of each clue, followed by its answer when withAnswers is
+// true.
+func clueText(clues []Clue, withAnswers bool) []string {
+	lines := make([]string, 0, len(clues))
+	for _, clue := range clues {
+		if withAnswers {
+			lines = append(lines, clue.Text+": "+clue.Answer)
+		} else {
+			lines = append(lines, clue.Text)
+		}
+	}
+	return lines
+}
+
+// WordEntries makes an entry for each word, shown just as it is given.
+func WordEntries(words []string) []WordEntry {
+	entries := make([]WordEntry, 0, len(words))
+	for _, word := range words {
+		entries = append(entries, WordEntry{Display: word})
+	}
+	return entries
+}
+
+// DisplayText returns the form of the word shown in the word list, or as the answer
+// to its clue.
+func (e WordEntry) DisplayText() string {
+	switch {
+	case e.Display != "":
+		return e.Display
+	case e.Word != "":
+		return e.Word
+	}
+	return e.Grid
+}
+
+// GridWord returns the letters of the word placed in the grid.  Letters of the
+// alphabet are kept as they are; see normalizeGridWord.
+func (e WordEntry) GridWord(alphabet []rune) string {
+	if e.Grid != "" {
+		return normalizeGridWord(e.Grid, alphabet)
+	}
+	return normalizeGridWord(e.DisplayText(), alphabet)
+}
+
 func ReadWordsFromFile(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -22,7 +137,7 @@ func ReadWordsFromFile(filePath string) ([]string, error) {
 		if word == "" {
 			continue
 		}
-		if !isValidWord(word) {
+		if !isValidWord(normalizeWord(word)) {
 			return nil, errors.New("Invalid word found in the input file: " + word)
 		}
 		words = append(words, word)
@@ -35,6 +150,192 @@ func ReadWordsFromFile(filePath string) ([]string, error) {
 	return words, nil
 }
 
+// WordSource is a file of search words named in a config: a text file with one word
+// per line, or a CSV file (by its .csv extension) with the words in one column, the
+// first unless Column says otherwise, and optionally their clues in another.  Columns
+// are given by number, counted from 1, or by name; naming a column means the first row
+// holds the column names, as does Header.  In YAML a source is either the file name or
+// a mapping.
+type WordSource struct {
+	File       string `yaml:"file"`
+	Column     string `yaml:"column"`
+	ClueColumn string `yaml:"clue_column"`
+	Header     bool   `yaml:"header"`
+}
+
+func (s *WordSource) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*s = WordSource{File: value.Value}
+		return nil
+	}
+	// Decode through a type without this method to use the plain struct decoding
+	type plain WordSource
+	var source plain
+	if err := value.Decode(&source); err != nil {
+		return err
+	}
+	if source.File == "" {
+		return &ConfigError{Line: value.Line, Column: value.Column, Message: "include needs a file"}
+	}
+	*s = WordSource(source)
+	return nil
+}
+
+// Read returns the words in the source.  A relative file name is taken to be in dir.
+func (s WordSource) Read(dir string) ([]WordEntry, error) {
+	path := s.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		words, err := ReadWordsFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return WordEntries(words), nil
+	}
+	return s.readCSV(path)
+}
+
+func (s WordSource) readCSV(path string) ([]WordEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	var header []string
+	if s.Header || columnName(s.Column) || columnName(s.ClueColumn) {
+		header, err = reader.Read()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	column, err := csvColumn(s.Column, header, path)
+	if err != nil {
+		return nil, err
+	}
+	clueColumn := -1
+	if s.ClueColumn != "" {
+		clueColumn, err = csvColumn(s.ClueColumn, header, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]WordEntry, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if column >= len(record) || strings.TrimSpace(record[column]) == "" {
+			continue
+		}
+		entry := WordEntry{Display: strings.TrimSpace(record[column])}
+		if clueColumn >= 0 && clueColumn < len(record) {
+			entry.Clue = strings.TrimSpace(record[clueColumn])
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// columnName reports whether a CSV column is given by name rather than number.
+func columnName(column string) bool {
+	if column == "" {
+		return false
+	}
+	_, err := strconv.Atoi(column)
+	return err != nil
+}
+
+// csvColumn returns the index of a column given by number or by a name in header.  The
+// first column is the default.
+func csvColumn(column string, header []string, path string) (int, error) {
+	if column == "" {
+		return 0, nil
+	}
+	if number, err := strconv.Atoi(column); err == nil {
+		if number < 1 {
+			return 0, fmt.Errorf("column %d of %s does not exist (columns are counted from 1)", number, path)
+		}
+		return number - 1, nil
+	}
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(column)) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%s has no column named %q", path, column)
+}
+
+// normalizeWord returns the form of a word that is placed in the grid: uppercased
+// with spaces and punctuation removed.  Unlike normalizeGridWord it keeps diacritics.
+func normalizeWord(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(word))
+}
+
+// diacriticLetters spells uppercase letters with diacritics, and ligatures, in plain
+// A-Z letters.
+var diacriticLetters = map[rune]string{
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Ā': "A", 'Ă': "A", 'Ą': "A",
+	'Æ': "AE",
+	'Ç': "C", 'Ć': "C", 'Ĉ': "C", 'Ċ': "C", 'Č': "C",
+	'Ď': "D", 'Đ': "D", 'Ð': "D",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ĕ': "E", 'Ė': "E", 'Ę': "E", 'Ě': "E",
+	'Ĝ': "G", 'Ğ': "G", 'Ġ': "G", 'Ģ': "G",
+	'Ĥ': "H", 'Ħ': "H",
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ĩ': "I", 'Ī': "I", 'Ĭ': "I", 'Į': "I", 'İ': "I",
+	'Ĵ': "J",
+	'Ķ': "K",
+	'Ĺ': "L", 'Ļ': "L", 'Ľ': "L", 'Ŀ': "L", 'Ł': "L",
+	'Ñ': "N", 'Ń': "N", 'Ņ': "N", 'Ň': "N",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O", 'Ō': "O", 'Ŏ': "O", 'Ő': "O",
+	'Œ': "OE",
+	'Ŕ': "R", 'Ŗ': "R", 'Ř': "R",
+	'Ś': "S", 'Ŝ': "S", 'Ş': "S", 'Š': "S", 'ß': "SS", 'ẞ': "SS",
+	'Ţ': "T", 'Ť': "T", 'Ŧ': "T",
+	'Þ': "TH",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ũ': "U", 'Ū': "U", 'Ŭ': "U", 'Ů': "U", 'Ű': "U", 'Ų': "U",
+	'Ŵ': "W",
+	'Ý': "Y", 'Ÿ': "Y", 'Ŷ': "Y",
+	'Ź': "Z", 'Ż': "Z", 'Ž': "Z",
+}
+
+// normalizeGridWord turns a word as written into the letters placed in the grid: it
+// is uppercased, spaces, punctuation and combining marks are removed, and letters with
+// diacritics are spelled without them ("O'Brien" becomes OBRIEN and "Café" CAFE).
+// Letters in the alphabet are kept, so a puzzle with a Spanish alphabet keeps its Ñ.
+func normalizeGridWord(word string, alphabet []rune) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(word) {
+		switch {
+		case containsLetters(string(alphabet), []rune{r}):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.Is(unicode.Mn, r):
+		case diacriticLetters[r] != "":
+			b.WriteString(diacriticLetters[r])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func isValidWord(word string) bool {
 	if word == "" {
 		return false
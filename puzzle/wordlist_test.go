@@ -2,7 +2,11 @@ package puzzle
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestReadWordsFromFile(t *testing.T) {
@@ -57,3 +61,137 @@ func TestIsValidWord(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeGridWord(t *testing.T) {
+	testCases := []struct {
+		word     string
+		alphabet string
+		expected string
+	}{
+		{"Shih Tzu", "", "SHIHTZU"},
+		{"O'Brien", "", "OBRIEN"},
+		{"T-Rex", "", "TREX"},
+		{"Café", "", "CAFE"},
+		{"Straße", "", "STRASSE"},
+		{"Ærøskøbing", "", "AEROSKOBING"},
+		{"Piñata", "", "PINATA"},
+		{"Piñata", builtinAlphabets["es"], "PIÑATA"},
+		{"Crème brûlée", builtinAlphabets["fr"], "CRÈMEBRÛLÉE"},
+		{"R2-D2", "", "R2D2"},
+	}
+
+	for _, tc := range testCases {
+		if word := normalizeGridWord(tc.word, []rune(tc.alphabet)); word != tc.expected {
+			t.Errorf("Expected normalizeGridWord(%q, %q) to be %q, got %q", tc.word, tc.alphabet, tc.expected, word)
+		}
+	}
+}
+
+func TestWordEntryUnmarshalYAML(t *testing.T) {
+	var entries []WordEntry
+	err := yaml.Unmarshal([]byte(`
+- apple
+- display: "Shih Tzu"
+- display: "Dr. Who"
+  grid: DOCTORWHO
+- grid: KIWI
+`), &entries)
+	if err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	expected := []struct{ display, grid string }{
+		{"apple", "APPLE"}, {"Shih Tzu", "SHIHTZU"}, {"Dr. Who", "DOCTORWHO"}, {"KIWI", "KIWI"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.DisplayText() != expected[i].display || entry.GridWord(nil) != expected[i].grid {
+			t.Errorf("Expected entry %d to be %q/%q, got %q/%q", i, expected[i].display, expected[i].grid,
+				entry.DisplayText(), entry.GridWord(nil))
+		}
+	}
+
+	if err := yaml.Unmarshal([]byte("- {}\n"), &entries); err == nil {
+		t.Errorf("Expected an error for an entry without a display or grid form")
+	}
+}
+
+func TestWordClues(t *testing.T) {
+	var entries []WordEntry
+	err := yaml.Unmarshal([]byte(`
+- word: Paris
+  clue: Capital of France
+- word: Nile
+  clue: Longest river in Africa
+`), &entries)
+	if err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	clues, err := wordClues(entries)
+	if err != nil {
+		t.Fatalf("wordClues returned error: %v", err)
+	}
+	expected := []string{"Capital of France: Paris", "Longest river in Africa: Nile"}
+	if lines := clueText(clues, true); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected clues %v, got %v", expected, lines)
+	}
+	if word := entries[0].GridWord(nil); word != "PARIS" {
+		t.Errorf("Expected the answer PARIS in the grid, got %q", word)
+	}
+
+	if clues, err := wordClues(WordEntries([]string{"CAT"})); err != nil || clues != nil {
+		t.Errorf("Expected no clues for plain words, got %v, %v", clues, err)
+	}
+	if _, err := wordClues(append(entries, WordEntry{Display: "CAT"})); err == nil {
+		t.Errorf("Expected an error when only some words have clues")
+	}
+}
+
+func TestWordSourceRead(t *testing.T) {
+	dir := t.TempDir()
+	csvContent := "Word,Clue\nParis,Capital of France\n,\n\"New Delhi\",Capital of India\n"
+	if err := os.WriteFile(filepath.Join(dir, "bank.csv"), []byte(csvContent), 0644); err != nil {
+		t.Fatalf("Failed to write test CSV file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "words.txt"), []byte("apple\n\npear\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test words file: %v", err)
+	}
+
+	testCases := []struct {
+		source   WordSource
+		expected []string
+	}{
+		{WordSource{File: "words.txt"}, []string{"apple", "pear"}},
+		{WordSource{File: "bank.csv"}, []string{"Word", "Paris", "New Delhi"}},
+		{WordSource{File: "bank.csv", Header: true}, []string{"Paris", "New Delhi"}},
+		{WordSource{File: "bank.csv", Column: "2", Header: true}, []string{"Capital of France", "Capital of India"}},
+		{WordSource{File: "bank.csv", Column: "word", ClueColumn: "Clue"},
+			[]string{"Paris: Capital of France", "New Delhi: Capital of India"}},
+	}
+	for _, tc := range testCases {
+		entries, err := tc.source.Read(dir)
+		if err != nil {
+			t.Fatalf("Read(%+v) returned error: %v", tc.source, err)
+		}
+		words := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			if entry.Clue != "" {
+				words = append(words, entry.DisplayText()+": "+entry.Clue)
+			} else {
+				words = append(words, entry.DisplayText())
+			}
+		}
+		if !reflect.DeepEqual(words, tc.expected) {
+			t.Errorf("Expected %+v to read %q, got %q", tc.source, tc.expected, words)
+		}
+	}
+
+	for _, source := range []WordSource{{File: "bank.csv", Column: "Answer"}, {File: "bank.csv", Column: "0"},
+		{File: "missing.txt"}} {
+		if _, err := source.Read(dir); err == nil {
+			t.Errorf("Expected an error reading %+v", source)
+		}
+	}
+}
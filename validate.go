@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/craigk5n/wordsearch/puzzle"
+)
+
+// validateCommand implements "wordsearch validate", which checks YAML configs without
+// generating puzzles and prints each problem with the file, line and column it is at.
+// It returns the process exit code.
+func validateCommand(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s validate config.yaml...\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		fmt.Println("Error: at least one YAML input file is required.")
+		flags.Usage()
+		return 1
+	}
+
+	status := 0
+	for _, inputFile := range flags.Args() {
+		configs, err := puzzle.ParseConfigs(inputFile)
+		var configErrors puzzle.ConfigErrors
+		switch {
+		case err == nil && len(configs) == 1:
+			fmt.Printf("%s: OK\n", inputFile)
+			continue
+		case err == nil:
+			fmt.Printf("%s: OK (%d puzzles)\n", inputFile, len(configs))
+			continue
+		case errors.As(err, &configErrors):
+			for _, configError := range configErrors {
+				fmt.Printf("%s:%d:%d: %s\n", inputFile, configError.Line, configError.Column, configError.Message)
+			}
+		default:
+			fmt.Printf("%s: %v\n", inputFile, err)
+		}
+		status = 1
+	}
+	return status
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/craigk5n/wordsearch/puzzle"
+)
+
+// verifyCommand implements "wordsearch verify", which checks that every search word
+// appears exactly once in a puzzle grid, or in the grid of each puzzle in a YAML file.
+// It returns the process exit code.
+func verifyCommand(args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	inputFile := flags.String("i", "", "YAML input file with the search words")
+	gridFile := flags.String("g", "", "Text file with the puzzle grid (defaults to the config's output .txt file)")
+	wordsFile := flags.String("w", "", "Text file with one search word per line (instead of a YAML file)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s verify [-i config.yaml] [-g grid.txt] [-w words.txt]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	// The words to look for in each grid file
+	var grids []string
+	var wordLists [][]string
+	switch {
+	case *wordsFile != "":
+		words, err := puzzle.ReadWordsFromFile(*wordsFile)
+		if err != nil {
+			fmt.Printf("Error: Failed to read words file: %v\n", err)
+			return 1
+		}
+		// Look for the words as they are placed, with the letters the generator keeps
+		config := puzzle.PuzzleConfig{Words: puzzle.WordEntries(words)}
+		gridWords, err := config.GridWords()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		grids, wordLists = append(grids, *gridFile), append(wordLists, gridWords)
+	case *inputFile != "":
+		configs, err := puzzle.ParseConfigs(*inputFile)
+		if err != nil {
+			fmt.Printf("Error: Failed to parse YAML input file: %v\n", err)
+			return 1
+		}
+		if *gridFile != "" && len(configs) > 1 {
+			fmt.Printf("Error: %s has %d puzzles; leave out -g to check the grid of each one.\n", *inputFile,
+				len(configs))
+			return 1
+		}
+		for _, config := range configs {
+			words, err := config.GridWords()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return 1
+			}
+			grid := *gridFile
+			if grid == "" {
+				grid = config.OutputBasename + ".txt"
+			}
+			grids, wordLists = append(grids, grid), append(wordLists, words)
+		}
+	default:
+		fmt.Println("Error: YAML input file or words file is required.")
+		flags.Usage()
+		return 1
+	}
+	if grids[0] == "" {
+		fmt.Println("Error: grid file is required.")
+		flags.Usage()
+		return 1
+	}
+
+	status := 0
+	for i, gridFile := range grids {
+		if len(grids) > 1 {
+			fmt.Printf("%s:\n", gridFile)
+		}
+		if !verifyGrid(gridFile, wordLists[i]) {
+			status = 1
+		}
+	}
+	return status
+}
+
+// verifyGrid checks the grid in gridFile and prints the words that do not appear
+// exactly once.  It reports whether every word does.
+func verifyGrid(gridFile string, words []string) bool {
+	grid, err := puzzle.LoadGridFromFile(gridFile)
+	if err != nil {
+		fmt.Printf("Error: Failed to load grid: %v\n", err)
+		return false
+	}
+
+	problems := puzzle.Verify(grid, words)
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("%d of %d words do not appear exactly once\n", len(problems), len(words))
+		return false
+	}
+	fmt.Printf("All %d words appear exactly once\n", len(words))
+	return true
+}